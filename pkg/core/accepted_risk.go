@@ -0,0 +1,83 @@
+package core
+
+import (
+	"time"
+
+	"github.com/rediverio/sdk/pkg/ris"
+)
+
+// =============================================================================
+// Accepted Risks
+// =============================================================================
+
+// AcceptedRisk records a temporary acceptance of a finding, identified by its
+// fingerprint. The finding is suppressed until Until; afterwards it resurfaces.
+// A zero Until never expires.
+type AcceptedRisk struct {
+	Fingerprint string    `yaml:"fingerprint" json:"fingerprint"`
+	Reason      string    `yaml:"reason" json:"reason"`
+	Until       time.Time `yaml:"until" json:"until"`
+	Approver    string    `yaml:"approver" json:"approver"`
+}
+
+// Expired reports whether the acceptance has expired at the given time.
+func (a AcceptedRisk) Expired(now time.Time) bool {
+	return !a.Until.IsZero() && !now.Before(a.Until)
+}
+
+// ApplyAcceptedRisks splits findings into those still visible and those
+// suppressed by an active acceptance. Findings whose acceptance has expired
+// are visible again. See ApplyAcceptedRisksAt to also get the re-surfaced
+// findings separately for alerting.
+func ApplyAcceptedRisks(findings []ris.Finding, accepted []AcceptedRisk) (visible, suppressed []ris.Finding) {
+	visible, suppressed, _ = ApplyAcceptedRisksAt(findings, accepted, time.Now())
+	return visible, suppressed
+}
+
+// ApplyAcceptedRisksAt is ApplyAcceptedRisks evaluated at a given time.
+// resurfaced holds the findings whose acceptance expired; they are also
+// included in visible.
+//
+// Suppressed findings are returned with Status set to accepted_risk and a
+// Suppression recording the reason, approver and expiry. Input findings are
+// not modified.
+func ApplyAcceptedRisksAt(findings []ris.Finding, accepted []AcceptedRisk, now time.Time) (visible, suppressed, resurfaced []ris.Finding) {
+	byFingerprint := make(map[string]AcceptedRisk, len(accepted))
+	for _, a := range accepted {
+		if a.Fingerprint == "" {
+			continue
+		}
+		// Keep the longest-lived acceptance if a fingerprint is listed twice
+		if existing, ok := byFingerprint[a.Fingerprint]; ok && (existing.Until.IsZero() || (!a.Until.IsZero() && a.Until.Before(existing.Until))) {
+			continue
+		}
+		byFingerprint[a.Fingerprint] = a
+	}
+
+	visible = make([]ris.Finding, 0, len(findings))
+	for _, f := range findings {
+		a, ok := byFingerprint[f.Fingerprint]
+		switch {
+		case !ok || f.Fingerprint == "":
+			visible = append(visible, f)
+		case a.Expired(now):
+			visible = append(visible, f)
+			resurfaced = append(resurfaced, f)
+		default:
+			f.Status = ris.FindingStatusAcceptedRisk
+			f.Suppression = &ris.Suppression{
+				Kind:          "external",
+				Status:        "accepted",
+				Justification: a.Reason,
+				SuppressedBy:  a.Approver,
+			}
+			if !a.Until.IsZero() {
+				until := a.Until
+				f.Suppression.ExpiresAt = &until
+			}
+			suppressed = append(suppressed, f)
+		}
+	}
+
+	return visible, suppressed, resurfaced
+}
@@ -0,0 +1,187 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/rediverio/sdk/pkg/ris"
+)
+
+// =============================================================================
+// Advisory ID Normalization
+// =============================================================================
+
+// AdvisoryType classifies a security advisory identifier.
+type AdvisoryType string
+
+const (
+	AdvisoryTypeCVE   AdvisoryType = "cve"   // CVE-2021-44228
+	AdvisoryTypeGHSA  AdvisoryType = "ghsa"  // GHSA-jfh8-c2jp-5v3q
+	AdvisoryTypeOther AdvisoryType = "other" // RUSTSEC-2021-0001, GO-2022-0001, PYSEC-2021-1, ...
+)
+
+var (
+	cvePattern      = regexp.MustCompile(`^CVE-\d{4}-\d{4,}$`)
+	ghsaPattern     = regexp.MustCompile(`^GHSA(-[23456789cfghjmpqrvwx]{4}){3}$`)
+	advisoryPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]*-[A-Z0-9][A-Z0-9._:-]*$`)
+)
+
+// NormalizeAdvisoryID canonicalizes a vulnerability advisory ID and classifies
+// its type:
+//   - CVE IDs are upper-cased ("cve-2021-44228" -> "CVE-2021-44228")
+//   - GHSA IDs keep an upper-case prefix with a lower-case body, as GitHub
+//     publishes them ("ghsa-JFH8-C2JP-5V3Q" -> "GHSA-jfh8-c2jp-5v3q")
+//   - Other "PREFIX-..." IDs (RUSTSEC, GO, PYSEC, ...) are upper-cased
+//
+// An error is returned for empty or malformed IDs, including IDs with a CVE
+// or GHSA prefix that don't match the expected format.
+func NormalizeAdvisoryID(id string) (string, AdvisoryType, error) {
+	trimmed := strings.TrimSpace(id)
+	if trimmed == "" {
+		return "", "", fmt.Errorf("empty advisory ID")
+	}
+
+	upper := strings.ToUpper(trimmed)
+	switch {
+	case strings.HasPrefix(upper, "CVE-"):
+		if !cvePattern.MatchString(upper) {
+			return "", "", fmt.Errorf("malformed CVE ID: %q", id)
+		}
+		return upper, AdvisoryTypeCVE, nil
+
+	case strings.HasPrefix(upper, "GHSA-"):
+		normalized := "GHSA" + strings.ToLower(trimmed[len("GHSA"):])
+		if !ghsaPattern.MatchString(normalized) {
+			return "", "", fmt.Errorf("malformed GHSA ID: %q", id)
+		}
+		return normalized, AdvisoryTypeGHSA, nil
+
+	default:
+		if !advisoryPattern.MatchString(upper) {
+			return "", "", fmt.Errorf("malformed advisory ID: %q", id)
+		}
+		return upper, AdvisoryTypeOther, nil
+	}
+}
+
+// =============================================================================
+// Advisory Aliases
+// =============================================================================
+
+// advisoryAliases maps advisory IDs to the canonical ID of the same
+// vulnerability, preferring the CVE ID (e.g. the GHSA ID of Log4Shell maps to
+// CVE-2021-44228). Keys and values are in NormalizeAdvisoryID form. It is
+// seeded with a few well-known pairs; extend it with RegisterAdvisoryAlias.
+var (
+	advisoryAliasesMu sync.RWMutex
+	advisoryAliases   = map[string]string{
+		"GHSA-jfh8-c2jp-5v3q": "CVE-2021-44228", // Log4Shell
+		"GHSA-7rjr-3q55-vv33": "CVE-2021-45046", // Log4j incomplete fix
+		"GHSA-36p3-wjmg-h94x": "CVE-2022-22965", // Spring4Shell
+		"GHSA-j8r2-6x86-q33q": "CVE-2023-32681", // requests Proxy-Authorization leak
+	}
+)
+
+// DefaultAdvisoryAliases returns a copy of the registered advisory aliases,
+// suitable for CanonicalAdvisoryID and DedupByAdvisory.
+func DefaultAdvisoryAliases() map[string]string {
+	advisoryAliasesMu.RLock()
+	defer advisoryAliasesMu.RUnlock()
+	aliases := make(map[string]string, len(advisoryAliases))
+	for alias, canonical := range advisoryAliases {
+		aliases[alias] = canonical
+	}
+	return aliases
+}
+
+// RegisterAdvisoryAlias records that alias and canonical identify the same
+// vulnerability. Both IDs are normalized; malformed IDs are an error. It is
+// safe to call concurrently with DefaultAdvisoryAliases.
+func RegisterAdvisoryAlias(alias, canonical string) error {
+	a, _, err := NormalizeAdvisoryID(alias)
+	if err != nil {
+		return err
+	}
+	c, _, err := NormalizeAdvisoryID(canonical)
+	if err != nil {
+		return err
+	}
+	advisoryAliasesMu.Lock()
+	defer advisoryAliasesMu.Unlock()
+	advisoryAliases[a] = c
+	return nil
+}
+
+// LoadAdvisoryAliases reads a JSON object of alias -> canonical advisory IDs
+// (e.g. {"GHSA-xxxx-xxxx-xxxx": "CVE-2024-1234"}) and returns it merged over a
+// copy of DefaultAdvisoryAliases. IDs are normalized; malformed IDs are an
+// error. The registered aliases are not modified.
+func LoadAdvisoryAliases(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read advisory aliases: %w", err)
+	}
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse advisory aliases: %w", err)
+	}
+
+	aliases := DefaultAdvisoryAliases()
+	for alias, canonical := range raw {
+		a, _, err := NormalizeAdvisoryID(alias)
+		if err != nil {
+			return nil, err
+		}
+		c, _, err := NormalizeAdvisoryID(canonical)
+		if err != nil {
+			return nil, err
+		}
+		aliases[a] = c
+	}
+	return aliases, nil
+}
+
+// CanonicalAdvisoryID returns the canonical ID of an advisory: its aliases
+// entry (followed through chains), or the normalized ID itself when it has
+// none. Unrecognized IDs are returned trimmed.
+func CanonicalAdvisoryID(id string, aliases map[string]string) string {
+	id = normalizeAdvisory(id)
+	// Bound the walk by the map size so a cycle cannot loop forever
+	for range len(aliases) {
+		canonical, ok := aliases[id]
+		if !ok || canonical == id {
+			break
+		}
+		id = canonical
+	}
+	return id
+}
+
+// DedupByAdvisory drops findings that report the same vulnerability as an
+// earlier finding under an aliased advisory ID, e.g. a GHSA and a CVE for the
+// same package version from different feeds. Findings match on the
+// normalized package name, version and canonical advisory ID; the first one
+// is kept. Findings without a package or advisory ID are kept as-is.
+func DedupByAdvisory(findings []ris.Finding, aliases map[string]string) []ris.Finding {
+	seen := make(map[string]bool, len(findings))
+	result := make([]ris.Finding, 0, len(findings))
+	for _, f := range findings {
+		v := f.Vulnerability
+		if v == nil || v.Package == "" || v.CVEID == "" {
+			result = append(result, f)
+			continue
+		}
+		key := normalizePackageName(v.Package) + "@" + normalizePackageVersion(v.AffectedVersion) +
+			"|" + CanonicalAdvisoryID(v.CVEID, aliases)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, f)
+	}
+	return result
+}
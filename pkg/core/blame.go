@@ -0,0 +1,141 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rediverio/sdk/pkg/ris"
+)
+
+// =============================================================================
+// Git Blame Enrichment
+// =============================================================================
+
+// blameTimeout bounds a single git blame invocation.
+const blameTimeout = 30 * time.Second
+
+// blameNotTrackedMarkers are git stderr fragments indicating the file is not
+// under version control; these are treated as a no-op rather than an error.
+var blameNotTrackedMarkers = []string{
+	"not a git repository",
+	"no such path",
+	"is outside repository",
+	"no such ref",
+}
+
+// EnrichWithBlame runs git blame for the finding's start line and populates
+// Author, AuthorEmail, CommitDate and Location.CommitSHA, so findings can be
+// routed to the developer who introduced them.
+//
+// It is a no-op (returns nil) when the finding has no file location, when the
+// file is not tracked by git, or when the line is not committed yet.
+// Fields already set on the finding are not overwritten.
+func EnrichWithBlame(ctx context.Context, finding *ris.Finding, repoDir string) error {
+	if finding == nil || finding.Location == nil || finding.Location.Path == "" || finding.Location.StartLine <= 0 {
+		return nil
+	}
+
+	path := finding.Location.Path
+	if filepath.IsAbs(path) {
+		rel, err := filepath.Rel(repoDir, path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return nil // Outside the repository
+		}
+		path = rel
+	}
+
+	line := finding.Location.StartLine
+	result, err := ExecuteScanner(ctx, &ExecConfig{
+		Binary:  "git",
+		Args:    []string{"blame", "--porcelain", "-L", fmt.Sprintf("%d,%d", line, line), "--", filepath.ToSlash(path)},
+		WorkDir: repoDir,
+		Timeout: blameTimeout,
+	})
+	if err != nil {
+		return fmt.Errorf("git blame: %w", err)
+	}
+	if result.Error != nil {
+		return fmt.Errorf("git blame: %w", result.Error)
+	}
+	if result.ExitCode != 0 {
+		stderr := strings.ToLower(string(result.Stderr))
+		for _, marker := range blameNotTrackedMarkers {
+			if strings.Contains(stderr, marker) {
+				return nil
+			}
+		}
+		return fmt.Errorf("git blame exited with code %d: %s", result.ExitCode, strings.TrimSpace(string(result.Stderr)))
+	}
+
+	info := parseBlamePorcelain(result.Stdout)
+	if info.sha == "" || strings.Trim(info.sha, "0") == "" {
+		return nil // Uncommitted line
+	}
+
+	if finding.Location.CommitSHA == "" {
+		finding.Location.CommitSHA = info.sha
+	}
+	if finding.Author == "" {
+		finding.Author = info.author
+	}
+	if finding.AuthorEmail == "" {
+		finding.AuthorEmail = info.authorEmail
+	}
+	if finding.CommitDate == nil && !info.authorTime.IsZero() {
+		t := info.authorTime
+		finding.CommitDate = &t
+	}
+
+	return nil
+}
+
+// blameInfo holds the fields extracted from git blame --porcelain output.
+type blameInfo struct {
+	sha         string
+	author      string
+	authorEmail string
+	authorTime  time.Time
+}
+
+// parseBlamePorcelain extracts commit metadata from git blame --porcelain
+// output for a single line.
+func parseBlamePorcelain(output []byte) blameInfo {
+	var info blameInfo
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+
+	first := true
+	for scanner.Scan() {
+		line := scanner.Text()
+		if first {
+			if fields := strings.Fields(line); len(fields) > 0 {
+				info.sha = fields[0]
+			}
+			first = false
+			continue
+		}
+		// The content line (prefixed with a tab) ends the header
+		if strings.HasPrefix(line, "\t") {
+			break
+		}
+
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "author":
+			info.author = value
+		case "author-mail":
+			info.authorEmail = strings.TrimSuffix(strings.TrimPrefix(value, "<"), ">")
+		case "author-time":
+			if sec, err := strconv.ParseInt(value, 10, 64); err == nil {
+				info.authorTime = time.Unix(sec, 0).UTC()
+			}
+		}
+	}
+
+	return info
+}
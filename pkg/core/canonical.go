@@ -0,0 +1,167 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rediverio/sdk/pkg/ris"
+	"github.com/rediverio/sdk/pkg/shared/fingerprint"
+)
+
+// =============================================================================
+// Cross-Tool Canonical Finding ID
+// =============================================================================
+
+// CanonicalFindingID returns an ID that is stable across scanners, so the
+// same vulnerability reported by different tools collides.
+//
+// For SCA findings (a vulnerability with a package and advisory ID) it hashes
+// the normalized package name, version and advisory ID. Unlike the
+// per-scanner fingerprint (see GenerateScaFingerprint), it ignores every
+// scanner-specific detail such as rule IDs, titles and how each tool spells
+// the package or version, so two scanners reporting "CVE-2021-44228 in
+// log4j-core 2.14.1" produce the same ID.
+//
+// Other findings have no cross-tool identity; their Fingerprint is returned
+// unchanged (which may be empty).
+func CanonicalFindingID(f ris.Finding) string {
+	v := f.Vulnerability
+	if v == nil || v.Package == "" || v.CVEID == "" {
+		return f.Fingerprint
+	}
+
+	return fingerprint.Hash(fmt.Sprintf("canonical:sca:%s:%s:%s",
+		normalizePackageName(v.Package),
+		normalizePackageVersion(v.AffectedVersion),
+		normalizeAdvisory(v.CVEID),
+	))
+}
+
+// normalizeAdvisory canonicalizes an advisory ID via NormalizeAdvisoryID,
+// keeping unrecognized IDs as-is (trimmed).
+func normalizeAdvisory(id string) string {
+	id = strings.TrimSpace(id)
+	if normalized, _, err := NormalizeAdvisoryID(id); err == nil {
+		return normalized
+	}
+	return id
+}
+
+// normalizePackageName lower-cases a package name and unifies separators
+// ("_" and "." become "-"), following PEP 503 name normalization.
+func normalizePackageName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer("_", "-", ".", "-").Replace(name)
+}
+
+// normalizePackageVersion trims whitespace and a leading "v".
+func normalizePackageVersion(version string) string {
+	version = strings.TrimSpace(version)
+	return strings.TrimPrefix(strings.TrimPrefix(version, "v"), "V")
+}
+
+// =============================================================================
+// Cross-Scan Correlation
+// =============================================================================
+
+// FindingLink pairs a finding from each of two scans that report the same
+// vulnerable package, e.g. a source-level SCA finding and the container
+// image finding it ends up in. A and B point into the slices passed to
+// CorrelateFindings.
+type FindingLink struct {
+	A *ris.Finding
+	B *ris.Finding
+
+	// Package and Advisory are the normalized match key.
+	Package  string
+	Advisory string
+}
+
+// CorrelateFindings links findings of a and b that flag the same package and
+// advisory, compared after normalization (see CanonicalFindingID); versions
+// are ignored, since an image may be built from a different lockfile state.
+// Findings without a package or CVE ID are not correlated. Every matching
+// pair is returned, ordered by position in a, then in b.
+//
+// Links let teams trace a container vulnerability to a tracked dependency and
+// count it once in risk totals. Packages renamed by OS distributions (e.g.
+// "python3-requests" vs "requests") are not matched.
+func CorrelateFindings(a, b []ris.Finding) []FindingLink {
+	type key struct{ pkg, advisory string }
+	keyOf := func(f *ris.Finding) (key, bool) {
+		v := f.Vulnerability
+		if v == nil || v.Package == "" || v.CVEID == "" {
+			return key{}, false
+		}
+		return key{normalizePackageName(v.Package), normalizeAdvisory(v.CVEID)}, true
+	}
+
+	byKey := make(map[key][]int)
+	for i := range b {
+		if k, ok := keyOf(&b[i]); ok {
+			byKey[k] = append(byKey[k], i)
+		}
+	}
+
+	var links []FindingLink
+	for i := range a {
+		k, ok := keyOf(&a[i])
+		if !ok {
+			continue
+		}
+		for _, j := range byKey[k] {
+			links = append(links, FindingLink{A: &a[i], B: &b[j], Package: k.pkg, Advisory: k.advisory})
+		}
+	}
+	return links
+}
+
+// =============================================================================
+// Deterministic Report ID
+// =============================================================================
+
+// ReportMeta identifies what a report was produced for, as input to ReportID.
+type ReportMeta struct {
+	Repository string `yaml:"repository" json:"repository"`
+	CommitSHA  string `yaml:"commit_sha" json:"commit_sha"`
+	Branch     string `yaml:"branch" json:"branch"`
+	Scanner    string `yaml:"scanner" json:"scanner"`
+}
+
+// ReportID returns a stable ID for a report derived from its contents, so
+// re-uploading the same results (e.g. from a retried CI job) can be detected
+// and treated as a no-op. It hashes meta and the findings, and does not
+// depend on the order of findings.
+//
+// Each finding contributes its Fingerprint; findings without one contribute
+// a hash of their JSON encoding with per-run fields (ID, Provenance)
+// cleared, so a re-run of the same scan yields the same ID.
+func ReportID(findings []ris.Finding, meta ReportMeta) string {
+	keys := make([]string, len(findings))
+	for i, f := range findings {
+		if f.Fingerprint != "" {
+			keys[i] = f.Fingerprint
+			continue
+		}
+		f.ID = ""
+		f.Provenance = nil
+		data, err := json.Marshal(f)
+		if err != nil {
+			// Only unencodable Properties values can fail; fall back to
+			// the fields that identify the finding.
+			data = []byte(fmt.Sprintf("%s:%s:%s", f.Type, f.RuleID, f.Title))
+		}
+		keys[i] = fingerprint.Hash(string(data))
+	}
+	sort.Strings(keys)
+
+	return fingerprint.Hash(fmt.Sprintf("report:v1:%s:%s:%s:%s\n%s",
+		meta.Repository,
+		meta.CommitSHA,
+		meta.Branch,
+		meta.Scanner,
+		strings.Join(keys, "\n"),
+	))
+}
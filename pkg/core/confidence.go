@@ -0,0 +1,73 @@
+package core
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// Confidence Normalization
+// =============================================================================
+
+// Confidence is a detection confidence score from 0 to 100, stored in
+// ris.Finding.Confidence. Zero means the confidence is unknown.
+type Confidence int
+
+const (
+	ConfidenceUnknown  Confidence = 0
+	ConfidenceLow      Confidence = 50
+	ConfidenceMedium   Confidence = 70
+	ConfidenceHigh     Confidence = 90
+	ConfidenceVeryHigh Confidence = 95
+)
+
+// String returns the confidence band: "very-high", "high", "medium", "low"
+// or "unknown".
+func (c Confidence) String() string {
+	switch {
+	case c >= ConfidenceVeryHigh:
+		return "very-high"
+	case c >= ConfidenceHigh:
+		return "high"
+	case c >= ConfidenceMedium:
+		return "medium"
+	case c > ConfidenceUnknown:
+		return "low"
+	default:
+		return "unknown"
+	}
+}
+
+// NormalizeConfidence normalizes confidence values from different scanners
+// to a 0-100 score. It accepts:
+//   - labels such as "HIGH", "medium", "low", "very-high" (Semgrep, SARIF precision)
+//   - fractions in [0, 1] such as "0.8" (scaled to 80)
+//   - percentages in [0, 100] such as "85"
+//
+// Unrecognized values, including NaN and infinities, return ConfidenceUnknown.
+func NormalizeConfidence(s string) Confidence {
+	v := strings.ToLower(strings.TrimSpace(s))
+	switch v {
+	case "very-high", "very_high", "veryhigh", "certain", "confirmed":
+		return ConfidenceVeryHigh
+	case "high", "h":
+		return ConfidenceHigh
+	case "medium", "moderate", "med", "m", "firm":
+		return ConfidenceMedium
+	case "low", "l", "tentative":
+		return ConfidenceLow
+	}
+
+	f, err := strconv.ParseFloat(strings.TrimSuffix(v, "%"), 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) || f < 0 {
+		return ConfidenceUnknown
+	}
+	if f <= 1 && !strings.HasSuffix(v, "%") {
+		f *= 100
+	}
+	if f > 100 {
+		return ConfidenceUnknown
+	}
+	return Confidence(int(f + 0.5))
+}
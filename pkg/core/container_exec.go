@@ -0,0 +1,206 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// Containerized Scanner Execution
+// =============================================================================
+
+// Container runtimes supported by ContainerExecConfig.
+const (
+	ContainerRuntimeDocker = "docker"
+	ContainerRuntimePodman = "podman"
+)
+
+// containerWorkDir is where ExecConfig.WorkDir is mounted inside the container.
+const containerWorkDir = "/src"
+
+// containerCleanupTimeout bounds the "rm -f" that removes a container left
+// running after a timeout or cancellation.
+const containerCleanupTimeout = 30 * time.Second
+
+// VolumeMount is a host path mounted into the scanner container.
+type VolumeMount struct {
+	Source   string `yaml:"source" json:"source"`       // Host path
+	Target   string `yaml:"target" json:"target"`       // Container path
+	ReadOnly bool   `yaml:"read_only" json:"read_only"` // Mount read-only
+}
+
+// ContainerExecConfig runs a scanner inside a container via docker or podman.
+//
+// Exec.Binary is the entrypoint inside the image (empty uses the image's
+// default entrypoint) and Exec.Args its arguments. Exec.WorkDir is mounted
+// read-only at /src and used as the container working directory, so
+// arguments should refer to paths under /src. Exec.Env is passed into the
+// container; the host environment is not. Env values never appear on the
+// runtime command line: only "-e KEY" is passed, and the runtime reads the
+// value from its own environment, so credentials stay out of process
+// listings and logged command lines. Exec.Timeout and the output options
+// apply as for ExecuteScanner (on timeout the container itself is removed,
+// see ExecuteContainer); a relative ExpectOutputFile is resolved against
+// the host workdir (requires WritableWorkDir).
+//
+// Example:
+//
+//	cfg := &core.ContainerExecConfig{
+//	    Image: "aquasec/trivy:0.50.0",
+//	    Exec: core.ExecConfig{
+//	        Args:    []string{"fs", "--format", "json", "/src"},
+//	        WorkDir: repoDir,
+//	        Timeout: 10 * time.Minute,
+//	    },
+//	}
+//	result, err := core.ExecuteContainer(ctx, cfg)
+type ContainerExecConfig struct {
+	Exec ExecConfig
+
+	Image   string        // Container image (required)
+	Runtime string        // "docker" (default) or "podman"
+	Mounts  []VolumeMount // Additional volume mounts
+
+	// WritableWorkDir mounts the workdir read-write, for scanners that must
+	// write into the scanned tree (e.g. to write a report file).
+	WritableWorkDir bool
+
+	// Name is the container name. When empty, a unique "rediver-scan-..."
+	// name is generated.
+	Name string
+}
+
+// BuildArgs returns the runtime binary and the "run --rm ..." arguments for
+// the container invocation. Env variables are passed by name only, so the
+// runtime must be started with Exec.Env in its environment, as
+// ExecuteContainer does.
+func (c *ContainerExecConfig) BuildArgs() (string, []string, error) {
+	if c.Image == "" {
+		return "", nil, fmt.Errorf("container image is required")
+	}
+
+	runtime := c.Runtime
+	if runtime == "" {
+		runtime = ContainerRuntimeDocker
+	}
+	if runtime != ContainerRuntimeDocker && runtime != ContainerRuntimePodman {
+		return "", nil, fmt.Errorf("unsupported container runtime: %s", runtime)
+	}
+
+	name := c.Name
+	if name == "" {
+		name = newContainerName()
+	}
+
+	args := []string{"run", "--rm", "--name", name}
+	if c.Exec.Stdin != nil || c.Exec.StdinBytes != nil {
+		args = append(args, "-i") // Keep stdin attached
+	}
+
+	mounts := c.Mounts
+	if c.Exec.WorkDir != "" {
+		workDir, err := filepath.Abs(c.Exec.WorkDir)
+		if err != nil {
+			return "", nil, fmt.Errorf("resolve workdir: %w", err)
+		}
+		mounts = append([]VolumeMount{{Source: workDir, Target: containerWorkDir, ReadOnly: !c.WritableWorkDir}}, mounts...)
+		args = append(args, "-w", containerWorkDir)
+	}
+	for _, m := range mounts {
+		if m.Source == "" || m.Target == "" {
+			return "", nil, fmt.Errorf("volume mount requires source and target")
+		}
+		spec := m.Source + ":" + m.Target
+		if m.ReadOnly {
+			spec += ":ro"
+		}
+		args = append(args, "-v", spec)
+	}
+
+	// Sort for deterministic command lines
+	keys := make([]string, 0, len(c.Exec.Env))
+	for k := range c.Exec.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		// Name only: the value comes from the runtime's environment (see
+		// ExecuteContainer), keeping secrets out of the command line
+		args = append(args, "-e", k)
+	}
+
+	if c.Exec.Binary != "" {
+		args = append(args, "--entrypoint", c.Exec.Binary)
+	}
+
+	args = append(args, c.Image)
+	args = append(args, c.Exec.Args...)
+
+	return runtime, args, nil
+}
+
+// ExecuteContainer runs a scanner inside a container and returns its result.
+// See ContainerExecConfig for how the embedded ExecConfig is interpreted.
+//
+// Stopping the runtime client does not stop the container, so when
+// Exec.Timeout expires or ctx is cancelled the container is removed with
+// "<runtime> rm -f <name>". A failed removal is reported in
+// ExecResult.Error unless it already holds an error.
+func ExecuteContainer(ctx context.Context, cfg *ContainerExecConfig) (*ExecResult, error) {
+	named := *cfg
+	if named.Name == "" {
+		named.Name = newContainerName()
+	}
+	runtime, args, err := named.BuildArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	runCtx := ctx
+	if cfg.Exec.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, cfg.Exec.Timeout)
+		defer cancel()
+	}
+
+	hostCfg := cfg.Exec
+	hostCfg.ExpectOutputFile = resolveOutputFile(&cfg.Exec) // Host path, before WorkDir is cleared
+	hostCfg.Binary = runtime
+	hostCfg.Args = args
+	hostCfg.WorkDir = ""
+	// Keep Env: the runtime process inherits it and forwards each "-e KEY"
+
+	result, err := ExecuteScanner(runCtx, &hostCfg)
+	if runCtx.Err() != nil {
+		if rmErr := removeContainer(runtime, named.Name); rmErr != nil && result != nil && result.Error == nil {
+			result.Error = rmErr
+		}
+	}
+	return result, err
+}
+
+// removeContainer force-removes a container, using a fresh context since the
+// scan's context is already done.
+func removeContainer(runtime, name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), containerCleanupTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, runtime, "rm", "-f", name).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to remove container %s: %w: %s", name, err, firstLine(string(out)))
+	}
+	return nil
+}
+
+// newContainerName returns a unique container name.
+func newContainerName() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return "rediver-scan-" + hex.EncodeToString(b)
+}
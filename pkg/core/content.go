@@ -0,0 +1,64 @@
+package core
+
+import (
+	"bytes"
+	"net/http"
+	"unicode/utf8"
+)
+
+// =============================================================================
+// Content Sniffing
+// =============================================================================
+
+// SniffWindow is the number of leading bytes inspected by IsBinary.
+const SniffWindow = 8 * 1024
+
+// binaryThreshold is the ratio of suspicious bytes above which content is
+// considered binary.
+const binaryThreshold = 0.3
+
+// IsBinary reports whether content looks like binary data rather than text.
+// Only the first SniffWindow bytes are inspected. Content is binary if it
+// contains a NUL byte, or if more than 30% of it is invalid UTF-8 or
+// non-whitespace control characters.
+//
+// Secret scanners should skip binary content to avoid false positives on
+// compiled artifacts.
+func IsBinary(content []byte) bool {
+	if len(content) > SniffWindow {
+		content = content[:SniffWindow]
+	}
+	if len(content) == 0 {
+		return false
+	}
+	if bytes.IndexByte(content, 0) >= 0 {
+		return true
+	}
+
+	suspicious := 0
+	for i := 0; i < len(content); {
+		r, size := utf8.DecodeRune(content[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			// A multi-byte rune truncated by the window is not suspicious
+			if len(content) == SniffWindow && !utf8.FullRune(content[i:]) {
+				i = len(content)
+				continue
+			}
+			suspicious++
+		case r < 0x20 && r != '\n' && r != '\r' && r != '\t' && r != '\f' && r != '\b':
+			suspicious++
+		}
+		i += size
+	}
+
+	return float64(suspicious)/float64(len(content)) > binaryThreshold
+}
+
+// DetectMIME returns the MIME type of content (e.g. "text/plain; charset=utf-8",
+// "application/zip"), using the WHATWG sniffing algorithm. At most the first
+// 512 bytes are considered. Returns "application/octet-stream" if the type
+// cannot be determined.
+func DetectMIME(content []byte) string {
+	return http.DetectContentType(content)
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// Scan Coverage
+// =============================================================================
+
+// ScanCoverage records how many files a scan analyzed and why the rest were
+// skipped, e.g. "scanned 1,203 of 1,250 files; 47 skipped (binary)".
+// Adapters whose scanners report scanned and skipped files populate it with
+// AddScanned and AddSkipped. It is not safe for concurrent use.
+type ScanCoverage struct {
+	Scanned int `yaml:"scanned" json:"scanned"`
+	Skipped int `yaml:"skipped" json:"skipped"`
+	Total   int `yaml:"total" json:"total"`
+
+	// SkipReasons counts skipped files by reason (e.g. "binary", "too_large").
+	SkipReasons map[string]int `yaml:"skip_reasons,omitempty" json:"skip_reasons,omitempty"`
+}
+
+// AddScanned records n scanned files.
+func (c *ScanCoverage) AddScanned(n int) {
+	c.Scanned += n
+	c.Total += n
+}
+
+// AddSkipped records n files skipped for reason. An empty reason is counted
+// as "unknown".
+func (c *ScanCoverage) AddSkipped(reason string, n int) {
+	if reason == "" {
+		reason = "unknown"
+	}
+	if c.SkipReasons == nil {
+		c.SkipReasons = make(map[string]int)
+	}
+	c.Skipped += n
+	c.Total += n
+	c.SkipReasons[reason] += n
+}
+
+// Merge adds the counts of other, e.g. to combine the coverage of several
+// scanners run over disjoint file sets.
+func (c *ScanCoverage) Merge(other ScanCoverage) {
+	c.Scanned += other.Scanned
+	c.Skipped += other.Skipped
+	c.Total += other.Total
+	for reason, n := range other.SkipReasons {
+		if c.SkipReasons == nil {
+			c.SkipReasons = make(map[string]int)
+		}
+		c.SkipReasons[reason] += n
+	}
+}
+
+// Percentage returns the share of files scanned, from 0 to 100. It is 0
+// when there were no files.
+func (c ScanCoverage) Percentage() float64 {
+	if c.Total <= 0 {
+		return 0
+	}
+	return float64(c.Scanned) / float64(c.Total) * 100
+}
+
+// String renders the coverage for reports, e.g.
+// "scanned 1,203 of 1,250 files; 47 skipped (binary)". Skip reasons are
+// listed by count, highest first.
+func (c ScanCoverage) String() string {
+	s := fmt.Sprintf("scanned %s of %s files", formatThousands(c.Scanned), formatThousands(c.Total))
+	if c.Skipped == 0 {
+		return s
+	}
+
+	reasons := make([]string, 0, len(c.SkipReasons))
+	for reason := range c.SkipReasons {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if c.SkipReasons[reasons[i]] != c.SkipReasons[reasons[j]] {
+			return c.SkipReasons[reasons[i]] > c.SkipReasons[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+
+	s += fmt.Sprintf("; %s skipped", formatThousands(c.Skipped))
+	switch len(reasons) {
+	case 0:
+	case 1:
+		s += " (" + reasons[0] + ")"
+	default:
+		parts := make([]string, len(reasons))
+		for i, reason := range reasons {
+			parts[i] = fmt.Sprintf("%s: %s", reason, formatThousands(c.SkipReasons[reason]))
+		}
+		s += " (" + strings.Join(parts, ", ") + ")"
+	}
+	return s
+}
+
+// formatThousands formats n with comma thousands separators.
+func formatThousands(n int) string {
+	s := strconv.Itoa(n)
+	neg := strings.HasPrefix(s, "-")
+	if neg {
+		s = s[1:]
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	if neg {
+		return "-" + s
+	}
+	return s
+}
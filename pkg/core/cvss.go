@@ -0,0 +1,514 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// =============================================================================
+// CVSS v3 Vector Parsing
+// =============================================================================
+
+// cvssV3Metrics lists the allowed values for every CVSS v3.x metric.
+var cvssV3Metrics = map[string][]string{
+	// Base
+	"AV": {"N", "A", "L", "P"},
+	"AC": {"L", "H"},
+	"PR": {"N", "L", "H"},
+	"UI": {"N", "R"},
+	"S":  {"U", "C"},
+	"C":  {"H", "L", "N"},
+	"I":  {"H", "L", "N"},
+	"A":  {"H", "L", "N"},
+	// Temporal
+	"E":  {"X", "H", "F", "P", "U"},
+	"RL": {"X", "U", "W", "T", "O"},
+	"RC": {"X", "C", "R", "U"},
+	// Environmental
+	"CR":  {"X", "H", "M", "L"},
+	"IR":  {"X", "H", "M", "L"},
+	"AR":  {"X", "H", "M", "L"},
+	"MAV": {"X", "N", "A", "L", "P"},
+	"MAC": {"X", "L", "H"},
+	"MPR": {"X", "N", "L", "H"},
+	"MUI": {"X", "N", "R"},
+	"MS":  {"X", "U", "C"},
+	"MC":  {"X", "H", "L", "N"},
+	"MI":  {"X", "H", "L", "N"},
+	"MA":  {"X", "H", "L", "N"},
+}
+
+// cvssV3BaseMetrics are the metrics every v3.x vector must contain.
+var cvssV3BaseMetrics = []string{"AV", "AC", "PR", "UI", "S", "C", "I", "A"}
+
+// CVSSVector is a parsed CVSS v3.x vector.
+type CVSSVector struct {
+	// Version is "3.0" or "3.1".
+	Version string
+	// Metrics maps metric abbreviations (e.g. "AV") to values (e.g. "N").
+	Metrics map[string]string
+
+	// ImpactSubscore ("how bad if exploited") and ExploitabilitySubscore
+	// ("how easy to exploit") are the base score subscores of the v3.1
+	// specification, rounded to one decimal as NVD displays them. They are
+	// set by ParseCVSSVector and reflect the base metrics only.
+	ImpactSubscore         float64
+	ExploitabilitySubscore float64
+}
+
+// ParseCVSSVector parses a CVSS v3.0/v3.1 vector string such as
+// "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H". All base metrics are
+// required; temporal and environmental metrics are optional.
+func ParseCVSSVector(vector string) (*CVSSVector, error) {
+	parts := strings.Split(strings.TrimSpace(vector), "/")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("invalid CVSS vector %q", vector)
+	}
+
+	version, ok := strings.CutPrefix(parts[0], "CVSS:")
+	if !ok {
+		return nil, fmt.Errorf("invalid CVSS vector %q: missing CVSS version prefix", vector)
+	}
+	if version != "3.0" && version != "3.1" {
+		return nil, fmt.Errorf("unsupported CVSS version %q", version)
+	}
+
+	v := &CVSSVector{Version: version, Metrics: make(map[string]string, len(parts)-1)}
+	for _, part := range parts[1:] {
+		key, value, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid CVSS metric %q", part)
+		}
+		allowed, known := cvssV3Metrics[key]
+		if !known {
+			return nil, fmt.Errorf("unknown CVSS metric %q", key)
+		}
+		if _, dup := v.Metrics[key]; dup {
+			return nil, fmt.Errorf("duplicate CVSS metric %q", key)
+		}
+		if !containsString(allowed, value) {
+			return nil, fmt.Errorf("invalid value %q for CVSS metric %s", value, key)
+		}
+		v.Metrics[key] = value
+	}
+
+	for _, key := range cvssV3BaseMetrics {
+		if _, ok := v.Metrics[key]; !ok {
+			return nil, fmt.Errorf("CVSS vector %q is missing base metric %s", vector, key)
+		}
+	}
+
+	v.ImpactSubscore = roundToTenth(math.Max(v.baseImpact(), 0))
+	v.ExploitabilitySubscore = roundToTenth(v.baseExploitability())
+
+	return v, nil
+}
+
+// metric returns the value of a metric, or "X" (not defined) if absent.
+func (v *CVSSVector) metric(key string) string {
+	if value, ok := v.Metrics[key]; ok {
+		return value
+	}
+	return "X"
+}
+
+// modified returns the value of a modified base metric (e.g. "MAV"), falling
+// back to the base metric when it is not defined.
+func (v *CVSSVector) modified(base string) string {
+	if value := v.metric("M" + base); value != "X" {
+		return value
+	}
+	return v.metric(base)
+}
+
+// baseImpact computes the unrounded base impact subscore.
+func (v *CVSSVector) baseImpact() float64 {
+	iss := 1 - (1-cvssCIAWeight(v.metric("C")))*(1-cvssCIAWeight(v.metric("I")))*(1-cvssCIAWeight(v.metric("A")))
+	if v.metric("S") == "C" {
+		return 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	return 6.42 * iss
+}
+
+// baseExploitability computes the unrounded base exploitability subscore.
+func (v *CVSSVector) baseExploitability() float64 {
+	return 8.22 * cvssAVWeight(v.metric("AV")) * cvssACWeight(v.metric("AC")) *
+		cvssPRWeight(v.metric("PR"), v.metric("S") == "C") * cvssUIWeight(v.metric("UI"))
+}
+
+// BaseScore computes the CVSS v3.x base score.
+func (v *CVSSVector) BaseScore() float64 {
+	changed := v.metric("S") == "C"
+	impact := v.baseImpact()
+	exploitability := v.baseExploitability()
+
+	if impact <= 0 {
+		return 0
+	}
+	if changed {
+		return v.roundUp(math.Min(1.08*(impact+exploitability), 10))
+	}
+	return v.roundUp(math.Min(impact+exploitability, 10))
+}
+
+// EnvironmentalScore computes the CVSS v3.x environmental score using the
+// vector's own temporal and environmental metrics. Metrics that are not
+// present default to "X" (not defined), which leaves the base values in effect.
+func (v *CVSSVector) EnvironmentalScore() float64 {
+	changed := v.modified("S") == "C"
+
+	miss := math.Min(1-
+		(1-cvssRequirementWeight(v.metric("CR"))*cvssCIAWeight(v.modified("C")))*
+			(1-cvssRequirementWeight(v.metric("IR"))*cvssCIAWeight(v.modified("I")))*
+			(1-cvssRequirementWeight(v.metric("AR"))*cvssCIAWeight(v.modified("A"))),
+		0.915)
+
+	var impact float64
+	switch {
+	case !changed:
+		impact = 6.42 * miss
+	case v.Version == "3.0":
+		impact = 7.52*(miss-0.029) - 3.25*math.Pow(miss-0.02, 15)
+	default:
+		impact = 7.52*(miss-0.029) - 3.25*math.Pow(miss*0.9731-0.02, 13)
+	}
+
+	exploitability := 8.22 * cvssAVWeight(v.modified("AV")) * cvssACWeight(v.modified("AC")) *
+		cvssPRWeight(v.modified("PR"), changed) * cvssUIWeight(v.modified("UI"))
+
+	if impact <= 0 {
+		return 0
+	}
+
+	temporal := cvssExploitWeight(v.metric("E")) * cvssRemediationWeight(v.metric("RL")) * cvssConfidenceWeight(v.metric("RC"))
+	if changed {
+		return v.roundUp(v.roundUp(math.Min(1.08*(impact+exploitability), 10)) * temporal)
+	}
+	return v.roundUp(v.roundUp(math.Min(impact+exploitability, 10)) * temporal)
+}
+
+// roundUp implements the spec's Roundup function for the vector's version.
+// CVSS 3.1 defines it on integers to avoid floating point artifacts.
+func (v *CVSSVector) roundUp(x float64) float64 {
+	if v.Version == "3.0" {
+		return math.Ceil(x*10) / 10
+	}
+	i := int64(math.Round(x * 100000))
+	if i%10000 == 0 {
+		return float64(i) / 100000
+	}
+	return float64(i/10000+1) / 10
+}
+
+// cvssV3MetricOrder is the canonical metric order of the v3.1 specification.
+var cvssV3MetricOrder = []string{
+	"AV", "AC", "PR", "UI", "S", "C", "I", "A",
+	"E", "RL", "RC",
+	"CR", "IR", "AR", "MAV", "MAC", "MPR", "MUI", "MS", "MC", "MI", "MA",
+}
+
+// String returns the vector in canonical form: the "CVSS:3.x" prefix followed
+// by its metrics in specification order.
+func (v *CVSSVector) String() string {
+	parts := []string{"CVSS:" + v.Version}
+	for _, key := range cvssV3MetricOrder {
+		if value, ok := v.Metrics[key]; ok {
+			parts = append(parts, key+":"+value)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// cvssV2Metrics lists the allowed values for every CVSS v2 metric, in the
+// canonical order of the v2 specification.
+var cvssV2Metrics = []struct {
+	key    string
+	values []string
+}{
+	{"AV", []string{"L", "A", "N"}},
+	{"AC", []string{"H", "M", "L"}},
+	{"Au", []string{"M", "S", "N"}},
+	{"C", []string{"N", "P", "C"}},
+	{"I", []string{"N", "P", "C"}},
+	{"A", []string{"N", "P", "C"}},
+	{"E", []string{"U", "POC", "F", "H", "ND"}},
+	{"RL", []string{"OF", "TF", "W", "U", "ND"}},
+	{"RC", []string{"UC", "UR", "C", "ND"}},
+	{"CDP", []string{"N", "L", "LM", "MH", "H", "ND"}},
+	{"TD", []string{"N", "L", "M", "H", "ND"}},
+	{"CR", []string{"L", "M", "H", "ND"}},
+	{"IR", []string{"L", "M", "H", "ND"}},
+	{"AR", []string{"L", "M", "H", "ND"}},
+}
+
+// CanonicalizeVector rewrites a CVSS vector so that equivalent vectors are
+// byte-for-byte equal: metrics are put in the specification's order and
+// names and values are upper-cased (except the v2 "Au" metric, which keeps
+// its spelling). Use it before comparing or storing vectors.
+//
+// v3.0/v3.1 vectors keep their "CVSS:3.x/" prefix and are validated like
+// ParseCVSSVector. v2 vectors are returned without a prefix, as NVD prints
+// them; a "CVSS:2.0/" prefix or surrounding parentheses are accepted on
+// input. Other versions (e.g. 4.0) return an error.
+func CanonicalizeVector(vector string) (string, error) {
+	upper := strings.ToUpper(strings.TrimSpace(vector))
+	upper = strings.TrimSuffix(strings.TrimPrefix(upper, "("), ")")
+
+	if strings.HasPrefix(upper, "CVSS:3.") {
+		v, err := ParseCVSSVector(upper)
+		if err != nil {
+			return "", err
+		}
+		return v.String(), nil
+	}
+
+	if rest, ok := strings.CutPrefix(upper, "CVSS:"); ok && !strings.HasPrefix(rest, "2.0/") {
+		version, _, _ := strings.Cut(rest, "/")
+		return "", fmt.Errorf("unsupported CVSS version %q", version)
+	}
+	return canonicalizeV2Vector(strings.TrimPrefix(upper, "CVSS:2.0/"))
+}
+
+// canonicalizeV2Vector canonicalizes an upper-cased, unprefixed v2 vector.
+func canonicalizeV2Vector(vector string) (string, error) {
+	metrics := make(map[string]string)
+	for _, part := range strings.Split(vector, "/") {
+		key, value, ok := strings.Cut(part, ":")
+		if !ok {
+			return "", fmt.Errorf("invalid CVSS metric %q", part)
+		}
+		if _, dup := metrics[key]; dup {
+			return "", fmt.Errorf("duplicate CVSS metric %q", key)
+		}
+		metrics[key] = value
+	}
+
+	parts := make([]string, 0, len(metrics))
+	for i, m := range cvssV2Metrics {
+		value, ok := metrics[strings.ToUpper(m.key)]
+		if !ok {
+			if i < 6 { // Base metrics are required
+				return "", fmt.Errorf("CVSS vector %q is missing base metric %s", vector, m.key)
+			}
+			continue
+		}
+		if !containsString(m.values, value) {
+			return "", fmt.Errorf("invalid value %q for CVSS metric %s", value, m.key)
+		}
+		parts = append(parts, m.key+":"+value)
+		delete(metrics, strings.ToUpper(m.key))
+	}
+	for key := range metrics {
+		return "", fmt.Errorf("unknown CVSS metric %q", key)
+	}
+	return strings.Join(parts, "/"), nil
+}
+
+// roundToTenth rounds x to one decimal place.
+func roundToTenth(x float64) float64 {
+	return math.Round(x*10) / 10
+}
+
+// SummarizeVector returns a short English description of how exploitable a
+// CVSS v3.x vector is, covering attack vector, attack complexity and
+// privileges required, e.g. "Network / Low complexity / No privileges".
+func SummarizeVector(vector string) (string, error) {
+	v, err := ParseCVSSVector(vector)
+	if err != nil {
+		return "", err
+	}
+
+	attackVector := map[string]string{
+		"N": "Network",
+		"A": "Adjacent network",
+		"L": "Local",
+		"P": "Physical",
+	}[v.metric("AV")]
+
+	complexity := map[string]string{
+		"L": "Low complexity",
+		"H": "High complexity",
+	}[v.metric("AC")]
+
+	privileges := map[string]string{
+		"N": "No privileges",
+		"L": "Low privileges",
+		"H": "High privileges",
+	}[v.metric("PR")]
+
+	return attackVector + " / " + complexity + " / " + privileges, nil
+}
+
+// =============================================================================
+// CVSS Environmental Metrics
+// =============================================================================
+
+// EnvModifiers holds CVSS v3.x environmental metrics describing the affected
+// asset. Each field takes the metric's abbreviated value; an empty value keeps
+// whatever the vector specifies (or "X", not defined, if the vector has none).
+type EnvModifiers struct {
+	// Security requirements: H (high), M (medium), L (low), X
+	CR string `yaml:"cr" json:"cr,omitempty"` // Confidentiality requirement
+	IR string `yaml:"ir" json:"ir,omitempty"` // Integrity requirement
+	AR string `yaml:"ar" json:"ar,omitempty"` // Availability requirement
+
+	// Modified base metrics
+	MAV string `yaml:"mav" json:"mav,omitempty"` // Attack vector: N, A, L, P
+	MAC string `yaml:"mac" json:"mac,omitempty"` // Attack complexity: L, H
+	MPR string `yaml:"mpr" json:"mpr,omitempty"` // Privileges required: N, L, H
+	MUI string `yaml:"mui" json:"mui,omitempty"` // User interaction: N, R
+	MS  string `yaml:"ms" json:"ms,omitempty"`   // Scope: U, C
+	MC  string `yaml:"mc" json:"mc,omitempty"`   // Confidentiality impact: H, L, N
+	MI  string `yaml:"mi" json:"mi,omitempty"`   // Integrity impact: H, L, N
+	MA  string `yaml:"ma" json:"ma,omitempty"`   // Availability impact: H, L, N
+}
+
+// ApplyEnvironmentalMetrics computes the CVSS v3.x environmental score of a
+// vector after applying the given modifiers, so vulnerabilities on high-value
+// assets can be boosted (e.g. CR:H for a PCI system) and those on throwaway
+// systems down-ranked.
+//
+// Vectors without environmental metrics are handled by treating them as not
+// defined; with empty modifiers the result equals the temporal score (or the
+// base score if the vector has no temporal metrics either).
+func ApplyEnvironmentalMetrics(vector string, modifiers EnvModifiers) (float64, error) {
+	v, err := ParseCVSSVector(vector)
+	if err != nil {
+		return 0, err
+	}
+
+	overrides := map[string]string{
+		"CR": modifiers.CR, "IR": modifiers.IR, "AR": modifiers.AR,
+		"MAV": modifiers.MAV, "MAC": modifiers.MAC, "MPR": modifiers.MPR, "MUI": modifiers.MUI,
+		"MS": modifiers.MS, "MC": modifiers.MC, "MI": modifiers.MI, "MA": modifiers.MA,
+	}
+	for key, value := range overrides {
+		if value == "" {
+			continue
+		}
+		value = strings.ToUpper(value)
+		if !containsString(cvssV3Metrics[key], value) {
+			return 0, fmt.Errorf("invalid value %q for CVSS metric %s", value, key)
+		}
+		v.Metrics[key] = value
+	}
+
+	return v.EnvironmentalScore(), nil
+}
+
+// =============================================================================
+// CVSS v3 Metric Weights
+// =============================================================================
+
+func cvssAVWeight(v string) float64 {
+	switch v {
+	case "N":
+		return 0.85
+	case "A":
+		return 0.62
+	case "L":
+		return 0.55
+	default: // P
+		return 0.2
+	}
+}
+
+func cvssACWeight(v string) float64 {
+	if v == "L" {
+		return 0.77
+	}
+	return 0.44
+}
+
+func cvssPRWeight(v string, scopeChanged bool) float64 {
+	switch v {
+	case "N":
+		return 0.85
+	case "L":
+		if scopeChanged {
+			return 0.68
+		}
+		return 0.62
+	default: // H
+		if scopeChanged {
+			return 0.5
+		}
+		return 0.27
+	}
+}
+
+func cvssUIWeight(v string) float64 {
+	if v == "N" {
+		return 0.85
+	}
+	return 0.62
+}
+
+func cvssCIAWeight(v string) float64 {
+	switch v {
+	case "H":
+		return 0.56
+	case "L":
+		return 0.22
+	default: // N
+		return 0
+	}
+}
+
+func cvssExploitWeight(v string) float64 {
+	switch v {
+	case "F":
+		return 0.97
+	case "P":
+		return 0.94
+	case "U":
+		return 0.91
+	default: // H, X
+		return 1
+	}
+}
+
+func cvssRemediationWeight(v string) float64 {
+	switch v {
+	case "W":
+		return 0.97
+	case "T":
+		return 0.96
+	case "O":
+		return 0.95
+	default: // U, X
+		return 1
+	}
+}
+
+func cvssConfidenceWeight(v string) float64 {
+	switch v {
+	case "R":
+		return 0.96
+	case "U":
+		return 0.92
+	default: // C, X
+		return 1
+	}
+}
+
+func cvssRequirementWeight(v string) float64 {
+	switch v {
+	case "H":
+		return 1.5
+	case "L":
+		return 0.5
+	default: // M, X
+		return 1
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package core
+
+import "testing"
+
+// Reference values are from the NVD CVSS v3.1 calculator.
+func TestParseCVSSVector_Subscores(t *testing.T) {
+	tests := []struct {
+		vector         string
+		base           float64
+		impact         float64
+		exploitability float64
+	}{
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 9.8, 5.9, 3.9},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", 10.0, 6.0, 3.9},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N", 6.1, 2.7, 2.8},
+		{"CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:H/A:H", 7.8, 5.9, 1.8},
+		{"CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:H/I:N/A:N", 5.9, 3.6, 2.2},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:H", 7.5, 3.6, 3.9},
+		{"CVSS:3.1/AV:P/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N", 1.6, 1.4, 0.1},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:N/I:N/A:N", 0, 0, 3.9},
+	}
+
+	for _, tt := range tests {
+		v, err := ParseCVSSVector(tt.vector)
+		if err != nil {
+			t.Fatalf("ParseCVSSVector(%q): %v", tt.vector, err)
+		}
+		if got := v.BaseScore(); got != tt.base {
+			t.Errorf("%s: BaseScore = %v, want %v", tt.vector, got, tt.base)
+		}
+		if v.ImpactSubscore != tt.impact {
+			t.Errorf("%s: ImpactSubscore = %v, want %v", tt.vector, v.ImpactSubscore, tt.impact)
+		}
+		if v.ExploitabilitySubscore != tt.exploitability {
+			t.Errorf("%s: ExploitabilitySubscore = %v, want %v", tt.vector, v.ExploitabilitySubscore, tt.exploitability)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/rediverio/sdk/pkg/ris"
+)
+
+// =============================================================================
+// Diff-Scoped Findings
+// =============================================================================
+
+// LineRange is an inclusive range of 1-indexed line numbers.
+type LineRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Contains reports whether line falls within the range.
+func (r LineRange) Contains(line int) bool {
+	return line >= r.Start && line <= r.End
+}
+
+// ParseUnifiedDiff parses a unified diff (e.g. "git diff" output) and returns
+// the added or modified line ranges of each file, keyed by the new file path.
+// Deleted files and pure deletions contribute no ranges.
+func ParseUnifiedDiff(diff []byte) map[string][]LineRange {
+	changed := make(map[string][]LineRange)
+
+	var file string
+	var newLine int
+	// Lines left in the current hunk, from its header counts. Tracking them
+	// tells an added line starting with "++ " apart from a "+++ " header.
+	var oldLeft, newLeft int
+
+	addLine := func(line int) {
+		ranges := changed[file]
+		if n := len(ranges); n > 0 && ranges[n-1].End == line-1 {
+			ranges[n-1].End = line
+			return
+		}
+		changed[file] = append(ranges, LineRange{Start: line, End: line})
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(diff))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		inHunk := oldLeft > 0 || newLeft > 0
+
+		switch {
+		case !inHunk && strings.HasPrefix(line, "+++ "):
+			file = parseDiffPath(line[4:])
+
+		case !inHunk && strings.HasPrefix(line, "@@ "):
+			hunk, ok := parseHunkHeader(line)
+			if ok && file != "" {
+				newLine, oldLeft, newLeft = hunk.newStart, hunk.oldCount, hunk.newCount
+			}
+
+		case !inHunk:
+			// File headers ("diff --git", "---", "index") outside hunks
+
+		case strings.HasPrefix(line, "+"):
+			addLine(newLine)
+			newLine++
+			newLeft--
+
+		case strings.HasPrefix(line, "-"):
+			// Removed lines don't exist in the new file
+			oldLeft--
+
+		case strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file"
+
+		default:
+			// Context line; some tools strip the leading space of empty ones
+			newLine++
+			oldLeft--
+			newLeft--
+		}
+	}
+
+	return changed
+}
+
+// parseDiffPath extracts the file path from a "+++ " header value.
+// Returns "" for /dev/null (deleted files).
+func parseDiffPath(s string) string {
+	// Strip an optional timestamp ("+++ b/file\t2024-01-01 ...")
+	if idx := strings.IndexByte(s, '\t'); idx >= 0 {
+		s = s[:idx]
+	}
+	s = strings.TrimSpace(s)
+	if s == "/dev/null" {
+		return ""
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	return strings.TrimPrefix(s, "b/")
+}
+
+// hunkHeader holds the line counts of a hunk header.
+type hunkHeader struct {
+	oldCount int
+	newStart int
+	newCount int
+}
+
+// parseHunkHeader parses a hunk header such as "@@ -10,7 +12,9 @@ func foo()".
+// An omitted count (as in "@@ -3 +3 @@") is 1.
+func parseHunkHeader(header string) (hunkHeader, bool) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return hunkHeader{}, false
+	}
+	_, oldCount, ok := parseHunkRange(fields[1][1:])
+	if !ok {
+		return hunkHeader{}, false
+	}
+	newStart, newCount, ok := parseHunkRange(fields[2][1:])
+	if !ok {
+		return hunkHeader{}, false
+	}
+	return hunkHeader{oldCount: oldCount, newStart: newStart, newCount: newCount}, true
+}
+
+// parseHunkRange parses the "start,count" part of a hunk header.
+func parseHunkRange(s string) (start, count int, ok bool) {
+	startStr, countStr, hasCount := strings.Cut(s, ",")
+	start, err := strconv.Atoi(startStr)
+	if err != nil {
+		return 0, 0, false
+	}
+	count = 1
+	if hasCount {
+		if count, err = strconv.Atoi(countStr); err != nil {
+			return 0, 0, false
+		}
+	}
+	return start, count, true
+}
+
+// FilterFindingsToDiff keeps only findings located on changed lines, for
+// "new code only" scanning of pull requests. A finding matches if its line
+// range (StartLine to EndLine) overlaps a changed range of its file.
+// Findings without a file location or start line are dropped.
+func FilterFindingsToDiff(findings []ris.Finding, changed map[string][]LineRange) []ris.Finding {
+	result := make([]ris.Finding, 0)
+	for _, f := range findings {
+		if f.Location == nil || f.Location.StartLine <= 0 {
+			continue
+		}
+
+		path := NormalizeFindingPath(f.Location.Path)
+		end := max(f.Location.EndLine, f.Location.StartLine)
+		for _, r := range changed[path] {
+			if f.Location.StartLine <= r.End && end >= r.Start {
+				result = append(result, f)
+				break
+			}
+		}
+	}
+	return result
+}
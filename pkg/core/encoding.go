@@ -0,0 +1,126 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// =============================================================================
+// Output Encoding Normalization
+// =============================================================================
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// cp1252High maps Windows-1252 bytes 0x80-0x9F to Unicode. The undefined
+// positions map to U+FFFD; 0xA0-0xFF are identical to Latin-1.
+var cp1252High = [32]rune{
+	'€', '�', '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', '�', 'Ž', '�',
+	'�', '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', '�', 'ž', 'Ÿ',
+}
+
+// NormalizeEncoding detects the encoding of scanner output and transcodes it
+// to UTF-8. Detection order:
+//  1. A byte order mark (UTF-8, UTF-16LE, UTF-16BE); the BOM is stripped
+//  2. BOM-less UTF-16, recognized by NUL bytes in alternating positions
+//  3. Valid UTF-8, returned unchanged
+//  4. Otherwise Windows-1252 (CP-1252), the usual Windows console encoding
+//
+// An error is returned for UTF-16 input with an odd number of bytes.
+func NormalizeEncoding(data []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(data, bomUTF8):
+		return data[len(bomUTF8):], nil
+	case bytes.HasPrefix(data, bomUTF16LE):
+		return decodeUTF16(data[len(bomUTF16LE):], false)
+	case bytes.HasPrefix(data, bomUTF16BE):
+		return decodeUTF16(data[len(bomUTF16BE):], true)
+	}
+
+	if bigEndian, ok := sniffUTF16(data); ok {
+		return decodeUTF16(data, bigEndian)
+	}
+
+	if utf8.Valid(data) {
+		return data, nil
+	}
+
+	return decodeCP1252(data), nil
+}
+
+// sniffUTF16 guesses whether BOM-less data is UTF-16 by looking for NUL bytes
+// concentrated in even (big-endian) or odd (little-endian) positions, which is
+// characteristic of mostly-ASCII text such as JSON.
+func sniffUTF16(data []byte) (bigEndian bool, ok bool) {
+	sample := data[:min(len(data), SniffWindow)]
+	if len(sample) < 4 {
+		return false, false
+	}
+
+	var evenNUL, oddNUL int
+	for i, b := range sample {
+		if b != 0 {
+			continue
+		}
+		if i%2 == 0 {
+			evenNUL++
+		} else {
+			oddNUL++
+		}
+	}
+
+	half := len(sample) / 2
+	switch {
+	case oddNUL > half*3/4 && evenNUL <= half/8:
+		return false, true
+	case evenNUL > half*3/4 && oddNUL <= half/8:
+		return true, true
+	default:
+		return false, false
+	}
+}
+
+// decodeUTF16 transcodes UTF-16 data to UTF-8.
+func decodeUTF16(data []byte, bigEndian bool) ([]byte, error) {
+	if len(data)%2 != 0 {
+		return nil, fmt.Errorf("invalid UTF-16 data: odd length %d", len(data))
+	}
+
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+		} else {
+			units[i] = uint16(data[2*i+1])<<8 | uint16(data[2*i])
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(units))
+	for _, r := range utf16.Decode(units) {
+		buf.WriteRune(r)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeCP1252 transcodes Windows-1252 data to UTF-8.
+func decodeCP1252(data []byte) []byte {
+	var buf bytes.Buffer
+	buf.Grow(len(data) + len(data)/4)
+	for _, b := range data {
+		switch {
+		case b < 0x80:
+			buf.WriteByte(b)
+		case b < 0xA0:
+			buf.WriteRune(cp1252High[b-0x80])
+		default:
+			buf.WriteRune(rune(b))
+		}
+	}
+	return buf.Bytes()
+}
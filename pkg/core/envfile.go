@@ -0,0 +1,148 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// =============================================================================
+// Environment Files
+// =============================================================================
+
+// envKeyPattern matches a valid dotenv variable name.
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// LoadEnvFile reads a dotenv file into a map suitable for ExecConfig.Env.
+//
+// Supported syntax:
+//   - KEY=value, with an optional "export " prefix
+//   - blank lines and "#" comments, including trailing comments after
+//     whitespace in unquoted values ("KEY=value # note")
+//   - single-quoted values, taken literally
+//   - double-quoted values, with \n, \r, \t, \" and \\ escapes
+//   - quoted values spanning several lines (e.g. PEM keys)
+//
+// Variable references such as ${HOME} are not expanded. When a key repeats,
+// the last value wins. Errors name the line but never include values, which
+// are often secrets; use MaskEnv before logging the result.
+func LoadEnvFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read env file: %w", err)
+	}
+	env, err := parseEnvFile(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return env, nil
+}
+
+// parseEnvFile parses dotenv content.
+func parseEnvFile(content string) (map[string]string, error) {
+	env := make(map[string]string)
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !ok || !envKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("line %d: expected KEY=value", lineNo)
+		}
+		value = strings.TrimLeft(value, " \t")
+
+		if value == "" || (value[0] != '"' && value[0] != '\'') {
+			if idx := strings.Index(value, " #"); idx >= 0 {
+				value = value[:idx]
+			}
+			env[key] = strings.TrimSpace(value)
+			continue
+		}
+
+		// Quoted value: find the closing quote, joining lines if needed
+		quote := value[0]
+		rest := value[1:]
+		for {
+			if end := closingQuote(rest, quote); end >= 0 {
+				trailing := strings.TrimSpace(rest[end+1:])
+				if trailing != "" && !strings.HasPrefix(trailing, "#") {
+					return nil, fmt.Errorf("line %d: unexpected characters after quoted value of %s", lineNo, key)
+				}
+				rest = rest[:end]
+				break
+			}
+			if i+1 >= len(lines) {
+				return nil, fmt.Errorf("line %d: unterminated quoted value of %s", lineNo, key)
+			}
+			i++
+			rest += "\n" + lines[i]
+		}
+
+		if quote == '"' {
+			rest = unescapeEnvValue(rest)
+		}
+		env[key] = rest
+	}
+
+	return env, nil
+}
+
+// closingQuote returns the index of the closing quote in s, skipping
+// backslash-escaped double quotes, or -1.
+func closingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// unescapeEnvValue resolves the escapes of a double-quoted value.
+func unescapeEnvValue(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case '"', '\\':
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// MaskEnv returns a copy of env with every value masked by MaskSecret, for
+// debug output of environments loaded with LoadEnvFile.
+func MaskEnv(env map[string]string) map[string]string {
+	masked := make(map[string]string, len(env))
+	for k, v := range env {
+		masked[k] = MaskSecret(v)
+	}
+	return masked
+}
@@ -2,10 +2,19 @@ package core
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"hash"
 	"io"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"sync"
 	"time"
 )
@@ -22,6 +31,127 @@ type ExecConfig struct {
 	Env     map[string]string // Environment variables
 	Timeout time.Duration     // Execution timeout
 	Verbose bool              // Stream output to logs
+
+	// FatalStderrPatterns are regular expressions matched against each stderr
+	// line. A match marks the result with HadFatalStderr and ErrorPattern, for
+	// scanners that report failures (e.g. "rate limit exceeded") but exit 0.
+	FatalStderrPatterns []string
+	// KillOnFatalStderr kills the scanner on the first fatal stderr match.
+	// By default the scanner is allowed to finish.
+	KillOnFatalStderr bool
+
+	// TailLines, when > 0, makes ExecuteScanner keep only the last N lines of
+	// stdout and stderr (in a ring buffer) instead of the full output. Useful
+	// for chatty scanners whose useful output is a final summary.
+	// When 0, the full output is captured.
+	TailLines int
+
+	// ExpectOutputFile is the report file for scanners that write results to
+	// a file (e.g. "-o report.json") instead of stdout. Relative paths are
+	// resolved against WorkDir. After the scanner exits, ExecuteScanner reads
+	// the file into ExecResult.Stdout (replacing the process stdout) and
+	// deletes it. Any stale file is removed before the scanner starts.
+	ExpectOutputFile string
+
+	// NormalizeOutputEncoding transcodes ExecResult.Stdout to UTF-8 (see
+	// NormalizeEncoding), for Windows scanners that emit UTF-16 or CP-1252.
+	// If transcoding fails, Stdout is left as captured and ExecResult.Error
+	// is set unless it already holds an earlier error.
+	NormalizeOutputEncoding bool
+
+	// LineFilter, if set, is applied to each output line (without its line
+	// ending) before it is captured or passed to an OutputHandler. Returning
+	// false drops the line; otherwise the returned string replaces it.
+	// See StripANSI for a built-in filter. Fatal stderr patterns are matched
+	// against the unfiltered line.
+	LineFilter LineFilter
+
+	// EnvAllowlist restricts which parent environment variables the scanner
+	// inherits, e.g. to pass PATH and HOME but block CI secrets from reaching
+	// untrusted scanner binaries. Entries are exact names or prefixes ending
+	// in "*" (e.g. "LC_*"). Variables in Env are always passed. When empty,
+	// the full parent environment is inherited.
+	EnvAllowlist []string
+
+	// HashOutput makes ExecuteScanner compute the SHA-256 of stdout while it
+	// is captured and store it in ExecResult.OutputHash, as a
+	// content-addressable cache key for the report. The hash covers the raw
+	// output, before LineFilter and TailLines are applied. When
+	// ExpectOutputFile is set, the output file is hashed instead.
+	HashOutput bool
+
+	// Stdin is fed to the scanner's standard input. The pipe is closed once
+	// the reader returns EOF, so scanners that read until EOF terminate.
+	// When neither Stdin nor StdinBytes is set, stdin is empty.
+	Stdin io.Reader
+	// StdinBytes is a convenience for Stdin when the input is in memory.
+	// It is mutually exclusive with Stdin.
+	StdinBytes []byte
+
+	// WarnOnEmptyOutput flags runs that exit 0 without writing anything to
+	// stdout (see ExecResult.IsEmpty) by setting ExecResult.EmptyOutput, and
+	// logs a warning when Verbose is set. Such runs usually mean a silent
+	// failure or a bad invocation rather than zero findings.
+	WarnOnEmptyOutput bool
+
+	// Wrapper is a command and its arguments prepended to the scanner
+	// invocation, e.g. {"nice", "-n", "10"} or {"taskset", "-c", "0-1"}, to
+	// throttle scanners on shared runners. The scanner binary and Args follow
+	// the wrapper's arguments. Timeout, Env and WorkDir apply to the whole
+	// wrapped invocation.
+	Wrapper []string
+
+	// InactivityTimeout kills the scanner when it writes no output line for
+	// this long, to catch scans that hang mid-way (e.g. on one huge file)
+	// sooner than Timeout would. The timer starts with the first line of
+	// output, so silent startup (e.g. vulnerability DB loading) is governed
+	// by Timeout alone. A stalled run has ExecResult.StalledAt set and an
+	// Error wrapping ErrScannerStalled. Only applies to StreamScanner and
+	// StreamScannerWithStop; 0 disables it.
+	InactivityTimeout time.Duration
+}
+
+// commandLine returns the program and arguments to execute for cfg, with
+// cfg.Wrapper, if any, prepended to the scanner binary and its arguments.
+func commandLine(cfg *ExecConfig) (string, []string) {
+	if len(cfg.Wrapper) == 0 {
+		return cfg.Binary, cfg.Args
+	}
+	args := make([]string, 0, len(cfg.Wrapper)+len(cfg.Args))
+	args = append(args, cfg.Wrapper[1:]...)
+	args = append(args, cfg.Binary)
+	args = append(args, cfg.Args...)
+	return cfg.Wrapper[0], args
+}
+
+// LineFilter transforms or drops a line of scanner output.
+type LineFilter func(line string) (string, bool)
+
+// ansiPattern matches ANSI escape sequences: CSI (colors, cursor movement),
+// OSC (titles, hyperlinks) and two-byte escapes.
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[@-Z\\-_]`)
+
+// StripANSICodes removes ANSI escape sequences (colors, cursor movement,
+// hyperlinks) from s, e.g. to parse severity keywords from colored output.
+func StripANSICodes(s string) string {
+	if strings.IndexByte(s, '\x1b') < 0 {
+		return s
+	}
+	return ansiPattern.ReplaceAllString(s, "")
+}
+
+// StripANSI is a LineFilter that removes ANSI escape sequences and drops
+// lines that are empty afterwards (e.g. progress bar redraws).
+func StripANSI(line string) (string, bool) {
+	stripped := StripANSICodes(line)
+	// Progress bars redraw with carriage returns; keep only the final state
+	if idx := strings.LastIndexByte(stripped, '\r'); idx >= 0 {
+		stripped = stripped[idx+1:]
+	}
+	if strings.TrimSpace(stripped) == "" && strings.TrimSpace(line) != "" {
+		return "", false
+	}
+	return stripped, true
 }
 
 // ExecResult holds the result of scanner execution.
@@ -31,6 +161,121 @@ type ExecResult struct {
 	Stderr     []byte
 	DurationMs int64
 	Error      error
+
+	// HadFatalStderr is true if a stderr line matched FatalStderrPatterns.
+	HadFatalStderr bool
+	// ErrorPattern is the first FatalStderrPatterns entry that matched.
+	ErrorPattern string
+
+	// OutputHash is the hex-encoded SHA-256 of stdout when
+	// ExecConfig.HashOutput is set; empty otherwise.
+	OutputHash string
+
+	// Stopped is true if a LineHandler stopped the scan early (see
+	// ErrStopScan). Output and ExitCode then reflect the killed process.
+	Stopped bool
+
+	// TimeToFirstByteMs is the time from process start to the first byte on
+	// stdout or stderr, which separates slow startup (e.g. vulnerability DB
+	// loading) from slow scanning. Only measured by StreamScanner and
+	// StreamScannerWithStop; 0 if not measured or nothing was written.
+	TimeToFirstByteMs int64
+
+	// EmptyOutput is true if ExecConfig.WarnOnEmptyOutput is set and the run
+	// IsEmpty.
+	EmptyOutput bool
+
+	// StdoutBytes and StderrBytes are the total number of bytes the scanner
+	// wrote, before LineFilter and TailLines are applied, for sizing memory
+	// budgets. With ExpectOutputFile, StdoutBytes is the size of the file.
+	StdoutBytes int64
+	StderrBytes int64
+
+	// StalledAt is when the scanner was killed for exceeding
+	// ExecConfig.InactivityTimeout; zero otherwise.
+	StalledAt time.Time
+}
+
+// IsEmpty reports whether the scanner succeeded (exit code 0, no error) but
+// wrote nothing other than whitespace to stdout. For scanners that always
+// print a report, even an empty one, this indicates that nothing was
+// scanned rather than that nothing was found.
+func (r *ExecResult) IsEmpty() bool {
+	return r.ExitCode == 0 && r.Error == nil && !r.Stopped && len(bytes.TrimSpace(r.Stdout)) == 0
+}
+
+// checkEmptyOutput applies ExecConfig.WarnOnEmptyOutput to a result.
+func checkEmptyOutput(cfg *ExecConfig, result *ExecResult) {
+	if !cfg.WarnOnEmptyOutput || !result.IsEmpty() {
+		return
+	}
+	result.EmptyOutput = true
+	if cfg.Verbose {
+		fmt.Printf("[%s] Warning: scanner exited 0 without output\n", cfg.Binary)
+	}
+}
+
+// stderrWatcher matches stderr lines against fatal patterns.
+type stderrWatcher struct {
+	patterns []*regexp.Regexp
+	sources  []string
+	onMatch  func()
+
+	mu      sync.Mutex
+	matched string
+	hit     bool
+}
+
+// newStderrWatcher compiles the configured fatal patterns.
+// Returns nil if no patterns are configured.
+func newStderrWatcher(patterns []string, onMatch func()) (*stderrWatcher, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	w := &stderrWatcher{onMatch: onMatch}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fatal stderr pattern %q: %w", p, err)
+		}
+		w.patterns = append(w.patterns, re)
+		w.sources = append(w.sources, p)
+	}
+	return w, nil
+}
+
+// check tests a stderr line and records the first matching pattern.
+func (w *stderrWatcher) check(line string) {
+	if w == nil {
+		return
+	}
+	for i, re := range w.patterns {
+		if !re.MatchString(line) {
+			continue
+		}
+		w.mu.Lock()
+		first := !w.hit
+		if first {
+			w.hit = true
+			w.matched = w.sources[i]
+		}
+		w.mu.Unlock()
+		if first && w.onMatch != nil {
+			w.onMatch()
+		}
+		return
+	}
+}
+
+// apply copies the match state to the result.
+func (w *stderrWatcher) apply(result *ExecResult) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	result.HadFatalStderr = w.hit
+	result.ErrorPattern = w.matched
 }
 
 // ExecuteScanner runs a scanner binary with real-time output streaming.
@@ -41,19 +286,33 @@ func ExecuteScanner(ctx context.Context, cfg *ExecConfig) (*ExecResult, error) {
 		defer cancel()
 	}
 
-	cmd := exec.CommandContext(ctx, cfg.Binary, cfg.Args...) //nolint:gosec // Scanner binary is configured, not user input
+	ctx, kill := context.WithCancel(ctx)
+	defer kill()
+
+	watcher, err := newFatalStderrWatcher(cfg, kill)
+	if err != nil {
+		return nil, err
+	}
+
+	outputFile := resolveOutputFile(cfg)
+	if outputFile != "" {
+		if err := os.Remove(outputFile); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove stale output file: %w", err)
+		}
+	}
+
+	name, args := commandLine(cfg)
+	cmd := exec.CommandContext(ctx, name, args...) //nolint:gosec // Scanner binary is configured, not user input
 
 	if cfg.WorkDir != "" {
 		cmd.Dir = cfg.WorkDir
 	}
 
 	// Set environment variables
-	if len(cfg.Env) > 0 {
-		env := cmd.Environ()
-		for k, v := range cfg.Env {
-			env = append(env, fmt.Sprintf("%s=%s", k, v))
-		}
-		cmd.Env = env
+	cmd.Env = buildEnv(cmd, cfg)
+
+	if cmd.Stdin, err = stdinReader(cfg); err != nil {
+		return nil, err
 	}
 
 	// Create pipes for stdout/stderr
@@ -76,15 +335,34 @@ func ExecuteScanner(ctx context.Context, cfg *ExecConfig) (*ExecResult, error) {
 	// Capture output with optional streaming
 	var wg sync.WaitGroup
 	var stdoutBuf, stderrBuf []byte
+	var stdoutHash hash.Hash
+	if cfg.HashOutput {
+		stdoutHash = sha256.New()
+	}
+
+	stdoutCount := &countingReader{ReadCloser: stdout}
+	stderrCount := &countingReader{ReadCloser: stderr}
 
 	wg.Add(2)
 	go func() {
 		defer wg.Done()
-		stdoutBuf = captureOutput(stdout, cfg.Verbose, "stdout")
+		stdoutBuf = captureOutput(stdoutCount, captureOptions{
+			stream:    cfg.Verbose,
+			prefix:    "stdout",
+			tailLines: cfg.TailLines,
+			filter:    cfg.LineFilter,
+			hash:      stdoutHash,
+		})
 	}()
 	go func() {
 		defer wg.Done()
-		stderrBuf = captureOutput(stderr, cfg.Verbose, "stderr")
+		stderrBuf = captureOutput(stderrCount, captureOptions{
+			stream:    cfg.Verbose,
+			prefix:    "stderr",
+			watcher:   watcher,
+			tailLines: cfg.TailLines,
+			filter:    cfg.LineFilter,
+		})
 	}()
 
 	// Wait for output capture to complete
@@ -94,9 +372,11 @@ func ExecuteScanner(ctx context.Context, cfg *ExecConfig) (*ExecResult, error) {
 	err = cmd.Wait()
 
 	result := &ExecResult{
-		Stdout:     stdoutBuf,
-		Stderr:     stderrBuf,
-		DurationMs: time.Since(start).Milliseconds(),
+		Stdout:      stdoutBuf,
+		Stderr:      stderrBuf,
+		DurationMs:  time.Since(start).Milliseconds(),
+		StdoutBytes: stdoutCount.n,
+		StderrBytes: stderrCount.n,
 	}
 
 	if err != nil {
@@ -107,20 +387,168 @@ func ExecuteScanner(ctx context.Context, cfg *ExecConfig) (*ExecResult, error) {
 		}
 	}
 
+	watcher.apply(result)
+
+	if stdoutHash != nil {
+		result.OutputHash = hex.EncodeToString(stdoutHash.Sum(nil))
+	}
+
+	if outputFile != "" && result.Error == nil {
+		readOutputFile(outputFile, result)
+		if cfg.HashOutput && result.Error == nil {
+			sum := sha256.Sum256(result.Stdout)
+			result.OutputHash = hex.EncodeToString(sum[:])
+		}
+	}
+
+	if cfg.NormalizeOutputEncoding {
+		// The scanner has run: keep the result and report failures in it,
+		// leaving Stdout as captured
+		if normalized, err := NormalizeEncoding(result.Stdout); err != nil {
+			if result.Error == nil {
+				result.Error = fmt.Errorf("failed to normalize output encoding: %w", err)
+			}
+		} else {
+			result.Stdout = normalized
+		}
+	}
+
+	checkEmptyOutput(cfg, result)
+
 	return result, nil
 }
 
+// resolveOutputFile returns the absolute path of cfg.ExpectOutputFile, or ""
+// if none is configured.
+func resolveOutputFile(cfg *ExecConfig) string {
+	if cfg.ExpectOutputFile == "" {
+		return ""
+	}
+	if filepath.IsAbs(cfg.ExpectOutputFile) || cfg.WorkDir == "" {
+		return cfg.ExpectOutputFile
+	}
+	return filepath.Join(cfg.WorkDir, cfg.ExpectOutputFile)
+}
+
+// readOutputFile moves the contents of a scanner's report file into
+// result.Stdout and deletes the file. A missing file is only an error when
+// the scanner exited successfully; scanners that fail early often don't
+// write one.
+func readOutputFile(path string, result *ExecResult) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) || result.ExitCode == 0 {
+			result.Error = fmt.Errorf("failed to read output file: %w", err)
+		}
+		return
+	}
+	result.Stdout = data
+	result.StdoutBytes = int64(len(data))
+	_ = os.Remove(path)
+}
+
+// buildEnv returns the scanner environment: the parent environment (filtered
+// by cfg.EnvAllowlist) plus cfg.Env. Returns nil to inherit the parent
+// environment unchanged when neither is configured.
+func buildEnv(cmd *exec.Cmd, cfg *ExecConfig) []string {
+	if len(cfg.Env) == 0 && len(cfg.EnvAllowlist) == 0 {
+		return nil
+	}
+
+	env := cmd.Environ()
+	if len(cfg.EnvAllowlist) > 0 {
+		filtered := make([]string, 0, len(env))
+		for _, kv := range env {
+			name, _, _ := strings.Cut(kv, "=")
+			if envAllowed(name, cfg.EnvAllowlist) {
+				filtered = append(filtered, kv)
+			}
+		}
+		env = filtered
+	}
+
+	for k, v := range cfg.Env {
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+	return env
+}
+
+// envAllowed reports whether an environment variable name matches the
+// allowlist. Entries ending in "*" match by prefix.
+func envAllowed(name string, allowlist []string) bool {
+	for _, pattern := range allowlist {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if name == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// newFatalStderrWatcher creates a watcher for cfg.FatalStderrPatterns.
+// If KillOnFatalStderr is set, kill is invoked on the first match.
+func newFatalStderrWatcher(cfg *ExecConfig, kill context.CancelFunc) (*stderrWatcher, error) {
+	var onMatch func()
+	if cfg.KillOnFatalStderr {
+		onMatch = kill
+	}
+	return newStderrWatcher(cfg.FatalStderrPatterns, onMatch)
+}
+
+// stdinReader returns the reader for the scanner's stdin, or nil for none.
+// exec.Cmd copies a non-file reader into the stdin pipe and closes the pipe
+// after EOF, which is what lets EOF-driven scanners finish.
+func stdinReader(cfg *ExecConfig) (io.Reader, error) {
+	switch {
+	case cfg.Stdin != nil && cfg.StdinBytes != nil:
+		return nil, fmt.Errorf("stdin and stdin bytes are mutually exclusive")
+	case cfg.StdinBytes != nil:
+		return bytes.NewReader(cfg.StdinBytes), nil
+	default:
+		return cfg.Stdin, nil
+	}
+}
+
+// captureOptions configures how captureOutput handles a pipe.
+type captureOptions struct {
+	stream    bool           // Echo lines to stdout with prefix
+	prefix    string         // Log prefix ("stdout" or "stderr")
+	watcher   *stderrWatcher // Optional fatal pattern watcher
+	tailLines int            // Keep only the last N lines when > 0
+	filter    LineFilter     // Optional line transform/drop
+	hash      hash.Hash      // Optional hash of the raw (unfiltered) output
+}
+
 // captureOutput reads from a pipe and optionally streams to logs.
-func captureOutput(r io.ReadCloser, stream bool, prefix string) []byte {
+func captureOutput(r io.ReadCloser, opts captureOptions) []byte {
 	var buf []byte
+	var tail *lineRing
+	if opts.tailLines > 0 {
+		tail = newLineRing(opts.tailLines)
+	}
 	reader := bufio.NewReader(r)
 
 	for {
 		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 && opts.hash != nil {
+			opts.hash.Write(line)
+		}
 		if len(line) > 0 {
-			buf = append(buf, line...)
-			if stream {
-				fmt.Printf("[%s] %s", prefix, string(line))
+			// Match without the line ending, like the streaming path
+			opts.watcher.check(strings.TrimSuffix(strings.TrimSuffix(string(line), "\n"), "\r"))
+			line = applyLineFilter(opts.filter, line)
+		}
+		if len(line) > 0 {
+			if tail != nil {
+				tail.add(line)
+			} else {
+				buf = append(buf, line...)
+			}
+			if opts.stream {
+				fmt.Printf("[%s] %s", opts.prefix, string(line))
 			}
 		}
 		if err != nil {
@@ -128,6 +556,57 @@ func captureOutput(r io.ReadCloser, stream bool, prefix string) []byte {
 		}
 	}
 
+	if tail != nil {
+		return tail.bytes()
+	}
+	return buf
+}
+
+// applyLineFilter runs filter on a line read with its line ending, keeping
+// the original ending. Returns nil if the line is dropped.
+func applyLineFilter(filter LineFilter, line []byte) []byte {
+	if filter == nil {
+		return line
+	}
+	text := strings.TrimRight(string(line), "\r\n")
+	filtered, keep := filter(text)
+	if !keep {
+		return nil
+	}
+	return append([]byte(filtered), line[len(text):]...)
+}
+
+// lineRing is a fixed-size ring buffer holding the most recent lines.
+type lineRing struct {
+	lines [][]byte
+	next  int
+	full  bool
+}
+
+func newLineRing(size int) *lineRing {
+	return &lineRing{lines: make([][]byte, size)}
+}
+
+// add stores a line, evicting the oldest one when the ring is full.
+func (r *lineRing) add(line []byte) {
+	r.lines[r.next] = line
+	r.next = (r.next + 1) % len(r.lines)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// bytes returns the buffered lines, oldest first, concatenated.
+func (r *lineRing) bytes() []byte {
+	ordered := r.lines[:r.next]
+	if r.full {
+		ordered = append(append([][]byte{}, r.lines[r.next:]...), r.lines[:r.next]...)
+	}
+
+	var buf []byte
+	for _, line := range ordered {
+		buf = append(buf, line...)
+	}
 	return buf
 }
 
@@ -138,20 +617,69 @@ func captureOutput(r io.ReadCloser, stream bool, prefix string) []byte {
 // OutputHandler processes scanner output in real-time.
 type OutputHandler func(line string, isError bool)
 
+// LineHandler processes scanner output in real-time and can stop the scan
+// by returning an error (see StreamScannerWithStop).
+type LineHandler func(line string, isError bool) error
+
+// ErrStopScan is returned by a LineHandler to stop the scan early, e.g. to
+// fail fast on the first critical finding. It is not reported as an error.
+var ErrStopScan = errors.New("stop scan")
+
+// ErrScannerStalled is wrapped by ExecResult.Error when a scanner is killed
+// for exceeding ExecConfig.InactivityTimeout.
+var ErrScannerStalled = errors.New("scanner stalled")
+
 // StreamScanner runs a scanner with real-time output handling.
 func StreamScanner(ctx context.Context, cfg *ExecConfig, handler OutputHandler) (*ExecResult, error) {
+	var lineHandler LineHandler
+	if handler != nil {
+		lineHandler = func(line string, isError bool) error {
+			handler(line, isError)
+			return nil
+		}
+	}
+	return streamScanner(ctx, cfg, lineHandler)
+}
+
+// StreamScannerWithStop runs a scanner like StreamScanner, but lets the
+// handler end the scan early. When the handler returns an error, the
+// scanner's context is cancelled, which kills the process, and the handler
+// receives no further lines. Partial results are returned: the result holds
+// the output captured up to that point and has Stopped set. If the error is
+// ErrStopScan the returned error is nil; otherwise it is the handler's error,
+// returned together with the partial result.
+func StreamScannerWithStop(ctx context.Context, cfg *ExecConfig, handler LineHandler) (*ExecResult, error) {
+	return streamScanner(ctx, cfg, handler)
+}
+
+func streamScanner(ctx context.Context, cfg *ExecConfig, handler LineHandler) (*ExecResult, error) {
 	if cfg.Timeout > 0 {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
 		defer cancel()
 	}
 
-	cmd := exec.CommandContext(ctx, cfg.Binary, cfg.Args...) //nolint:gosec // Scanner binary is configured, not user input
+	ctx, kill := context.WithCancel(ctx)
+	defer kill()
+
+	watcher, err := newFatalStderrWatcher(cfg, kill)
+	if err != nil {
+		return nil, err
+	}
+
+	name, args := commandLine(cfg)
+	cmd := exec.CommandContext(ctx, name, args...) //nolint:gosec // Scanner binary is configured, not user input
 
 	if cfg.WorkDir != "" {
 		cmd.Dir = cfg.WorkDir
 	}
 
+	cmd.Env = buildEnv(cmd, cfg)
+
+	if cmd.Stdin, err = stdinReader(cfg); err != nil {
+		return nil, err
+	}
+
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
@@ -171,24 +699,33 @@ func StreamScanner(ctx context.Context, cfg *ExecConfig, handler OutputHandler)
 	// Stream output with handler
 	var wg sync.WaitGroup
 	var stdoutBuf, stderrBuf []byte
+	stop := &streamStop{handler: handler, kill: kill}
+	idle := newInactivityWatchdog(cfg.InactivityTimeout, kill)
+	firstByte := &firstByteTimer{}
+	stdoutCount := &countingReader{ReadCloser: firstByte.wrap(stdout)}
+	stderrCount := &countingReader{ReadCloser: firstByte.wrap(stderr)}
 
 	wg.Add(2)
 	go func() {
 		defer wg.Done()
-		stdoutBuf = streamWithHandler(stdout, handler, false)
+		stdoutBuf = streamWithHandler(stdoutCount, stop, false, nil, idle, cfg.LineFilter)
 	}()
 	go func() {
 		defer wg.Done()
-		stderrBuf = streamWithHandler(stderr, handler, true)
+		stderrBuf = streamWithHandler(stderrCount, stop, true, watcher, idle, cfg.LineFilter)
 	}()
 
 	wg.Wait()
+	idle.stop()
 	err = cmd.Wait()
 
 	result := &ExecResult{
-		Stdout:     stdoutBuf,
-		Stderr:     stderrBuf,
-		DurationMs: time.Since(start).Milliseconds(),
+		Stdout:            stdoutBuf,
+		Stderr:            stderrBuf,
+		DurationMs:        time.Since(start).Milliseconds(),
+		TimeToFirstByteMs: firstByte.sinceMs(start),
+		StdoutBytes:       stdoutCount.n,
+		StderrBytes:       stderrCount.n,
 	}
 
 	if err != nil {
@@ -199,33 +736,348 @@ func StreamScanner(ctx context.Context, cfg *ExecConfig, handler OutputHandler)
 		}
 	}
 
+	watcher.apply(result)
+
+	if stalledAt := idle.stalled(); !stalledAt.IsZero() {
+		result.StalledAt = stalledAt
+		result.Error = fmt.Errorf("%w: no output for %s", ErrScannerStalled, cfg.InactivityTimeout)
+	}
+
+	if stopErr := stop.reason(); stopErr != nil {
+		result.Stopped = true
+		result.Error = nil // The kill was requested, not a failure
+		if !errors.Is(stopErr, ErrStopScan) {
+			return result, fmt.Errorf("output handler: %w", stopErr)
+		}
+	}
+
+	checkEmptyOutput(cfg, result)
+
 	return result, nil
 }
 
-func streamWithHandler(r io.ReadCloser, handler OutputHandler, isError bool) []byte {
+// countingReader counts the bytes read through it. It is read by a single
+// goroutine, and n is only inspected after that goroutine has finished.
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// firstByteTimer records when the first byte is read from any of the
+// readers it wraps.
+type firstByteTimer struct {
+	mu sync.Mutex
+	at time.Time
+}
+
+// wrap returns r, recording the time of its first non-empty read.
+func (t *firstByteTimer) wrap(r io.ReadCloser) io.ReadCloser {
+	return &firstByteReader{ReadCloser: r, timer: t}
+}
+
+// sinceMs returns the milliseconds from start to the first byte, or 0 if
+// nothing was read.
+func (t *firstByteTimer) sinceMs(start time.Time) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.at.IsZero() {
+		return 0
+	}
+	return t.at.Sub(start).Milliseconds()
+}
+
+type firstByteReader struct {
+	io.ReadCloser
+	timer *firstByteTimer
+}
+
+func (r *firstByteReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		r.timer.mu.Lock()
+		if r.timer.at.IsZero() {
+			r.timer.at = time.Now()
+		}
+		r.timer.mu.Unlock()
+	}
+	return n, err
+}
+
+// streamStop delivers lines to a LineHandler from the stdout and stderr
+// goroutines and kills the scanner on the first handler error.
+type streamStop struct {
+	handler LineHandler
+	kill    context.CancelFunc
+
+	mu  sync.Mutex
+	err error
+}
+
+// deliver passes a line to the handler unless the scan has been stopped.
+func (s *streamStop) deliver(line string, isError bool) {
+	if s.handler == nil || s.reason() != nil {
+		return
+	}
+	if err := s.handler(line, isError); err != nil {
+		s.mu.Lock()
+		if s.err == nil {
+			s.err = err
+		}
+		s.mu.Unlock()
+		s.kill()
+	}
+}
+
+// reason returns the handler error that stopped the scan, if any.
+func (s *streamStop) reason() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+func streamWithHandler(r io.ReadCloser, stop *streamStop, isError bool, watcher *stderrWatcher, idle *inactivityWatchdog, filter LineFilter) []byte {
 	var buf []byte
 	scanner := bufio.NewScanner(r)
 
 	for scanner.Scan() {
+		idle.touch()
 		line := scanner.Text()
-		buf = append(buf, []byte(line+"\n")...)
-		if handler != nil {
-			handler(line, isError)
+		watcher.check(line)
+		if filter != nil {
+			var keep bool
+			if line, keep = filter(line); !keep {
+				continue
+			}
 		}
+		buf = append(buf, []byte(line+"\n")...)
+		stop.deliver(line, isError)
 	}
 
 	return buf
 }
 
+// inactivityWatchdog kills a streamed scanner that produces no output line
+// for its timeout. A nil watchdog (InactivityTimeout disabled) is a no-op.
+type inactivityWatchdog struct {
+	timeout time.Duration
+	kill    context.CancelFunc
+
+	mu        sync.Mutex
+	timer     *time.Timer
+	last      time.Time
+	stalledAt time.Time
+}
+
+func newInactivityWatchdog(timeout time.Duration, kill context.CancelFunc) *inactivityWatchdog {
+	if timeout <= 0 {
+		return nil
+	}
+	return &inactivityWatchdog{timeout: timeout, kill: kill}
+}
+
+// touch records output activity, arming the timer on the first call.
+func (w *inactivityWatchdog) touch() {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.last = time.Now()
+	if w.timer == nil {
+		w.timer = time.AfterFunc(w.timeout, w.fire)
+	} else if w.stalledAt.IsZero() {
+		w.timer.Reset(w.timeout)
+	}
+}
+
+// fire kills the scanner unless output arrived while the timer was firing.
+func (w *inactivityWatchdog) fire() {
+	w.mu.Lock()
+	if idle := time.Since(w.last); idle < w.timeout {
+		w.timer.Reset(w.timeout - idle)
+		w.mu.Unlock()
+		return
+	}
+	w.stalledAt = time.Now()
+	w.mu.Unlock()
+	w.kill()
+}
+
+// stalled returns when the scanner was killed for inactivity, or zero.
+func (w *inactivityWatchdog) stalled() time.Time {
+	if w == nil {
+		return time.Time{}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.stalledAt
+}
+
+// stop disarms the timer once the scanner has exited.
+func (w *inactivityWatchdog) stop() {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+}
+
+// =============================================================================
+// Scanner Execution Retry
+// =============================================================================
+
+// retryableStderrPatterns are stderr messages of transient failures (network
+// errors, rate limiting, unavailable upstreams) used by DefaultRetryable.
+// They match as case-insensitive substrings. Extend the list with
+// RegisterRetryableStderrPattern.
+var (
+	retryablePatternsMu     sync.RWMutex
+	retryableStderrPatterns = []string{
+		"connection reset",
+		"connection refused",
+		"broken pipe",
+		"i/o timeout",
+		"tls handshake timeout",
+		"temporary failure in name resolution",
+		"unexpected eof",
+		"429 too many requests",
+		"too many requests",
+		"rate limit",
+		"502 bad gateway",
+		"503 service unavailable",
+		"504 gateway timeout",
+	}
+)
+
+// RegisterRetryableStderrPattern adds a stderr substring that DefaultRetryable
+// treats as a transient failure, such as a scanner-specific mirror error. It
+// is safe to call concurrently with ExecuteScannerWithRetry.
+func RegisterRetryableStderrPattern(pattern string) {
+	if strings.TrimSpace(pattern) == "" {
+		return
+	}
+	retryablePatternsMu.Lock()
+	defer retryablePatternsMu.Unlock()
+	retryableStderrPatterns = append(retryableStderrPatterns, pattern)
+}
+
+// RetryConfig configures ExecuteScannerWithRetry. The zero value makes a
+// single attempt; use DefaultRetryConfig for the recommended retries.
+type RetryConfig struct {
+	// MaxRetries is the number of retries after the first attempt. 0 (or a
+	// negative value) disables retries.
+	MaxRetries int
+
+	// Delay is the backoff before the first retry, doubled for each further
+	// retry (default 2s).
+	Delay time.Duration
+
+	// Retryable decides whether a finished run should be retried. It sees the
+	// whole result, including ExitCode and Stderr. Nil uses DefaultRetryable.
+	Retryable func(result *ExecResult) bool
+}
+
+// DefaultRetryConfig returns the recommended retry settings: 3 retries
+// starting at a 2s backoff, classified by DefaultRetryable.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxRetries: 3,
+		Delay:      2 * time.Second,
+		Retryable:  DefaultRetryable,
+	}
+}
+
+// DefaultRetryable retries failed runs whose stderr contains one of the
+// registered retryable patterns (see RegisterRetryableStderrPattern). Other failures, such as invalid flags or config
+// errors, are not retried.
+func DefaultRetryable(result *ExecResult) bool {
+	if result == nil || (result.ExitCode == 0 && result.Error == nil) {
+		return false
+	}
+	retryablePatternsMu.RLock()
+	defer retryablePatternsMu.RUnlock()
+	return StderrMatchesAny(result.Stderr, retryableStderrPatterns)
+}
+
+// StderrMatchesAny reports whether stderr contains any of patterns,
+// compared case-insensitively.
+func StderrMatchesAny(stderr []byte, patterns []string) bool {
+	lower := bytes.ToLower(stderr)
+	for _, p := range patterns {
+		if p != "" && bytes.Contains(lower, []byte(strings.ToLower(p))) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExecuteScannerWithRetry runs ExecuteScanner and retries runs that
+// retry.Retryable classifies as transient, with exponential backoff. It
+// returns the result of the last attempt. Errors from ExecuteScanner itself
+// (e.g. the binary failed to start) are returned without retrying, and no
+// retry is made once ctx is done.
+func ExecuteScannerWithRetry(ctx context.Context, cfg *ExecConfig, retry RetryConfig) (*ExecResult, error) {
+	if retry.MaxRetries < 0 {
+		retry.MaxRetries = 0
+	}
+	if retry.Delay == 0 {
+		retry.Delay = 2 * time.Second
+	}
+	if retry.Retryable == nil {
+		retry.Retryable = DefaultRetryable
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && cfg.Verbose {
+			fmt.Printf("[%s] Retrying (attempt %d/%d)\n", cfg.Binary, attempt, retry.MaxRetries)
+		}
+
+		result, err := ExecuteScanner(ctx, cfg)
+		if err != nil || attempt >= retry.MaxRetries || !retry.Retryable(result) {
+			return result, err
+		}
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-time.After(retry.Delay * time.Duration(1<<attempt)):
+		}
+	}
+}
+
 // =============================================================================
 // Scanner Installation Check
 // =============================================================================
 
 // CheckBinaryInstalled checks if a binary is installed and returns its version.
+// The version is the first line of the version command's output; use
+// CheckBinaryInstalledWith for tools that print it elsewhere.
 func CheckBinaryInstalled(ctx context.Context, binary string, versionArgs ...string) (bool, string, error) {
+	return CheckBinaryInstalledWith(ctx, binary, firstLine, versionArgs...)
+}
+
+// CheckBinaryInstalledWith checks if a binary is installed and extracts its
+// version from the version command's output using parser. A nil parser uses
+// ExtractVersion. versionArgs defaults to "--version".
+func CheckBinaryInstalledWith(ctx context.Context, binary string, parser func(output string) string, versionArgs ...string) (bool, string, error) {
 	if len(versionArgs) == 0 {
 		versionArgs = []string{"--version"}
 	}
+	if parser == nil {
+		parser = ExtractVersion
+	}
 
 	cmd := exec.CommandContext(ctx, binary, versionArgs...)
 	output, err := cmd.Output()
@@ -233,13 +1085,225 @@ func CheckBinaryInstalled(ctx context.Context, binary string, versionArgs ...str
 		return false, "", nil // Not installed
 	}
 
-	version := string(output)
-	// Try to extract first line as version
-	if idx := indexNewline(version); idx > 0 {
-		version = version[:idx]
+	return true, parser(string(output)), nil
+}
+
+// BinaryStatus is the result of probing one binary with
+// CheckBinariesInstalled.
+type BinaryStatus struct {
+	Installed bool
+	Version   string
+
+	// Err is set when the probe could not run to completion, e.g. because
+	// the context deadline passed. A binary that is simply missing has
+	// Installed false and no Err.
+	Err error
+}
+
+// maxConcurrentProbes bounds the version commands CheckBinariesInstalled
+// runs at once; probes are mostly process startup, not CPU-bound.
+const maxConcurrentProbes = 8
+
+// CheckBinariesInstalled probes several binaries concurrently, like
+// CheckBinaryInstalled for each, to cut the latency of preflight checks.
+// binaries maps each binary to its version arguments (nil for
+// "--version"). At most 8 probes run at once and all share ctx, so a
+// deadline on ctx bounds the whole check. Binaries not probed in time, or
+// whose probe was killed when ctx ended, get ctx.Err() in BinaryStatus.Err;
+// a binary found missing is reported as not installed even if ctx ends
+// afterwards.
+func CheckBinariesInstalled(ctx context.Context, binaries map[string][]string) map[string]BinaryStatus {
+	results := make(map[string]BinaryStatus, len(binaries))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentProbes)
+
+	for binary, versionArgs := range binaries {
+		wg.Add(1)
+		go func(binary string, versionArgs []string) {
+			defer wg.Done()
+
+			var status BinaryStatus
+			select {
+			case sem <- struct{}{}:
+				status = probeBinary(ctx, binary, versionArgs)
+				<-sem
+			case <-ctx.Done():
+				status.Err = ctx.Err() // Never probed
+			}
+
+			mu.Lock()
+			results[binary] = status
+			mu.Unlock()
+		}(binary, versionArgs)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// probeBinary runs one version command like CheckBinaryInstalled, but
+// reports ctx.Err() when the probe failed because ctx was done, instead of
+// treating it as a missing binary.
+func probeBinary(ctx context.Context, binary string, versionArgs []string) BinaryStatus {
+	if len(versionArgs) == 0 {
+		versionArgs = []string{"--version"}
+	}
+
+	output, err := exec.CommandContext(ctx, binary, versionArgs...).Output()
+	if err == nil {
+		return BinaryStatus{Installed: true, Version: firstLine(string(output))}
+	}
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		var exitErr *exec.ExitError
+		killed := errors.As(err, &exitErr) && !exitErr.Exited()
+		if killed || errors.Is(err, ctxErr) {
+			return BinaryStatus{Err: ctxErr}
+		}
+	}
+	return BinaryStatus{} // Not installed
+}
+
+// CheckBinaryVersion checks that a binary is installed with at least
+// minVersion and returns the installed version. The version is extracted with
+// ExtractVersion and compared with CompareSemver, so a pre-release of the
+// minimum (e.g. "1.2.0-beta.1" when "1.2.0" is required) is rejected. An
+// error is returned if the binary is not installed, its version is not a
+// semantic version, or it is older than minVersion. versionArgs defaults to
+// "--version".
+func CheckBinaryVersion(ctx context.Context, binary, minVersion string, versionArgs ...string) (string, error) {
+	installed, version, err := CheckBinaryInstalledWith(ctx, binary, ExtractVersion, versionArgs...)
+	if err != nil {
+		return "", err
+	}
+	if !installed {
+		return "", fmt.Errorf("%s is not installed", binary)
+	}
+
+	cmp, err := CompareSemver(version, minVersion)
+	if err != nil {
+		return version, fmt.Errorf("failed to check %s version: %w", binary, err)
+	}
+	if cmp < 0 {
+		return version, fmt.Errorf("%s version %s is older than required %s", binary, version, minVersion)
+	}
+	return version, nil
+}
+
+// versionPatterns are tried in order by ExtractVersion.
+var versionPatterns = []*regexp.Regexp{
+	// "Version: 0.50.1", "version v1.2.3", "trivy version 0.50.1"
+	regexp.MustCompile(`(?i)\bversion\b[\s:=]*v?(\d+(?:\.\d+)+(?:[-+][0-9A-Za-z.-]+)?)`),
+	// Any dotted version number, e.g. "semgrep 1.60.0" or "v8.18.2"
+	regexp.MustCompile(`v?(\d+(?:\.\d+)+(?:[-+][0-9A-Za-z.-]+)?)`),
+}
+
+// ExtractVersion is the default version parser for CheckBinaryInstalledWith.
+// It returns the version number following a "version" label (e.g. from
+// "Version: 0.50.1"), else the first dotted version number in the output,
+// else the first line.
+func ExtractVersion(output string) string {
+	for _, re := range versionPatterns {
+		if m := re.FindStringSubmatch(output); m != nil {
+			return m[1]
+		}
+	}
+	return firstLine(output)
+}
+
+// BinaryInstall is one installation of a binary found on PATH.
+type BinaryInstall struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+}
+
+// FindAllBinaries returns every executable named binary across the PATH
+// entries, in PATH order. Unlike exec.LookPath, which returns only the first
+// match, this exposes shadowed installs so callers can warn when several
+// versions of a scanner are installed. Entries that resolve to the same file
+// (duplicate PATH entries, symlinked directories such as /bin -> /usr/bin)
+// are reported once. Returns an error wrapping exec.ErrNotFound if there is
+// no match.
+func FindAllBinaries(binary string) ([]string, error) {
+	if strings.ContainsRune(binary, filepath.Separator) || strings.ContainsRune(binary, '/') {
+		path, err := exec.LookPath(binary)
+		if err != nil {
+			return nil, err
+		}
+		return []string{path}, nil
 	}
 
-	return true, version, nil
+	var paths []string
+	seen := make(map[string]bool)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		// Empty and "." entries mean the current directory, which
+		// exec.LookPath also refuses to search.
+		if dir == "" || dir == "." {
+			continue
+		}
+		path, err := exec.LookPath(filepath.Join(dir, binary))
+		if err != nil {
+			continue
+		}
+		key := path
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			key = resolved
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		paths = append(paths, path)
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%s not found in PATH: %w", binary, exec.ErrNotFound)
+	}
+	return paths, nil
+}
+
+// FindBinaryVersions returns every install of binary on PATH (see
+// FindAllBinaries) with its version, as reported by CheckBinaryInstalled.
+// The first entry is the one exec.LookPath and ExecuteScanner will run.
+// Installs whose version command fails are included with an empty Version.
+func FindBinaryVersions(ctx context.Context, binary string, versionArgs ...string) ([]BinaryInstall, error) {
+	paths, err := FindAllBinaries(binary)
+	if err != nil {
+		return nil, err
+	}
+
+	installs := make([]BinaryInstall, 0, len(paths))
+	for _, path := range paths {
+		_, version, _ := CheckBinaryInstalled(ctx, path, versionArgs...)
+		installs = append(installs, BinaryInstall{Path: path, Version: version})
+	}
+	return installs, nil
+}
+
+// HasVersionConflict reports whether installs contain more than one distinct
+// non-empty version.
+func HasVersionConflict(installs []BinaryInstall) bool {
+	var first string
+	for _, in := range installs {
+		if in.Version == "" {
+			continue
+		}
+		if first == "" {
+			first = in.Version
+		} else if in.Version != first {
+			return true
+		}
+	}
+	return false
+}
+
+// firstLine returns the first line of s.
+func firstLine(s string) string {
+	if idx := indexNewline(s); idx > 0 {
+		return s[:idx]
+	}
+	return s
 }
 
 func indexNewline(s string) int {
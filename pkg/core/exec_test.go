@@ -0,0 +1,47 @@
+package core
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExecuteScanner_StdinBytesReachesEOF(t *testing.T) {
+	if _, err := exec.LookPath("wc"); err != nil {
+		t.Skip("wc not available")
+	}
+
+	input := []byte(strings.Repeat("x", 128*1024)) // Larger than a pipe buffer
+
+	// wc -c only exits once stdin is closed; the timeout turns a hang into
+	// a failure instead of stalling the test run.
+	result, err := ExecuteScanner(context.Background(), &ExecConfig{
+		Binary:     "wc",
+		Args:       []string{"-c"},
+		Timeout:    10 * time.Second,
+		StdinBytes: input,
+	})
+	if err != nil {
+		t.Fatalf("ExecuteScanner: %v", err)
+	}
+	if result.Error != nil || result.ExitCode != 0 {
+		t.Fatalf("wc failed: exit=%d err=%v stderr=%q", result.ExitCode, result.Error, result.Stderr)
+	}
+
+	if got := strings.TrimSpace(string(result.Stdout)); got != "131072" {
+		t.Errorf("wc -c = %q, want %q", got, "131072")
+	}
+}
+
+func TestExecuteScanner_StdinExclusive(t *testing.T) {
+	_, err := ExecuteScanner(context.Background(), &ExecConfig{
+		Binary:     "wc",
+		Stdin:      strings.NewReader("a"),
+		StdinBytes: []byte("b"),
+	})
+	if err == nil {
+		t.Fatal("expected error when both Stdin and StdinBytes are set")
+	}
+}
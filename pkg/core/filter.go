@@ -0,0 +1,94 @@
+package core
+
+import (
+	"github.com/rediverio/sdk/pkg/ris"
+)
+
+// =============================================================================
+// Finding Filters
+// =============================================================================
+
+// FindingFilter configures which findings are kept by FilterFindings.
+// The zero value keeps every finding.
+type FindingFilter struct {
+	// OnlyFixable keeps only findings with an available fix (see ris.Finding.HasFix).
+	OnlyFixable bool `yaml:"only_fixable" json:"only_fixable"`
+
+	// MinConfidence drops findings whose confidence is below this score (0-100).
+	// Findings with unknown confidence (0) are kept. Zero disables the check.
+	MinConfidence Confidence `yaml:"min_confidence" json:"min_confidence"`
+
+	// IncludePaths keeps only findings whose location path matches one of
+	// these globs (see MatchGlob for the syntax). Empty includes every path.
+	IncludePaths []string `yaml:"include_paths" json:"include_paths"`
+
+	// ExcludePaths drops findings whose location path matches one of these
+	// globs. Exclusions take precedence over IncludePaths.
+	ExcludePaths []string `yaml:"exclude_paths" json:"exclude_paths"`
+}
+
+// Match reports whether a finding passes the filter.
+func (ff *FindingFilter) Match(f *ris.Finding) bool {
+	if ff == nil {
+		return true
+	}
+	if ff.OnlyFixable && !f.HasFix() {
+		return false
+	}
+	if ff.MinConfidence > 0 && f.Confidence > 0 && Confidence(f.Confidence) < ff.MinConfidence {
+		return false
+	}
+	if len(ff.IncludePaths) > 0 || len(ff.ExcludePaths) > 0 {
+		var path string
+		if f.Location != nil {
+			path = f.Location.Path
+		}
+		if len(ff.IncludePaths) > 0 && !matchAnyGlob(ff.IncludePaths, path) {
+			return false
+		}
+		if matchAnyGlob(ff.ExcludePaths, path) {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks that the path globs are well-formed.
+func (ff *FindingFilter) Validate() error {
+	if ff == nil {
+		return nil
+	}
+	for _, patterns := range [][]string{ff.IncludePaths, ff.ExcludePaths} {
+		for _, p := range patterns {
+			if err := ValidateGlob(p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// matchAnyGlob reports whether path matches any of the patterns.
+// An empty path matches nothing.
+func matchAnyGlob(patterns []string, path string) bool {
+	if path == "" {
+		return false
+	}
+	for _, p := range patterns {
+		if MatchGlob(p, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterFindings returns the findings that pass the filter, preserving order.
+func FilterFindings(findings []ris.Finding, filter *FindingFilter) []ris.Finding {
+	result := make([]ris.Finding, 0, len(findings))
+	for i := range findings {
+		if filter.Match(&findings[i]) {
+			result = append(result, findings[i])
+		}
+	}
+	return result
+}
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// =============================================================================
+// Path Globs
+// =============================================================================
+
+// NormalizeGlob rewrites a user-supplied glob into the canonical form used
+// by MatchGlob, translating common intents:
+//   - backslashes become "/", and "./" prefixes and repeated "/" are removed
+//   - a pattern without "/" matches at any depth ("*.go" becomes "**/*.go")
+//   - a leading "/" anchors the pattern at the root ("/*.go" becomes "*.go")
+//   - a trailing "/" matches everything below a directory ("vendor/"
+//     becomes "**/vendor/**"; "/vendor/" becomes "vendor/**")
+func NormalizeGlob(pattern string) string {
+	p := strings.TrimSpace(ToPosixPath(pattern))
+	if p == "" {
+		return ""
+	}
+
+	anchored := strings.HasPrefix(p, "/")
+	dirOnly := strings.HasSuffix(p, "/")
+
+	segments := make([]string, 0, strings.Count(p, "/")+1)
+	for _, seg := range strings.Split(p, "/") {
+		if seg == "" || seg == "." {
+			continue
+		}
+		segments = append(segments, seg)
+	}
+	if len(segments) == 0 {
+		return "**"
+	}
+
+	// A single segment without an explicit anchor matches at any depth,
+	// like .gitignore. Multi-segment patterns are relative to the root.
+	if !anchored && len(segments) == 1 && segments[0] != "**" {
+		segments = append([]string{"**"}, segments...)
+	}
+	if dirOnly && segments[len(segments)-1] != "**" {
+		segments = append(segments, "**")
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// ValidateGlob reports whether pattern is syntactically valid, e.g. that
+// character classes are closed.
+func ValidateGlob(pattern string) error {
+	for _, seg := range strings.Split(NormalizeGlob(pattern), "/") {
+		if _, err := path.Match(seg, ""); err != nil {
+			return fmt.Errorf("invalid glob %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// MatchGlob reports whether filePath matches pattern. The pattern is
+// normalized with NormalizeGlob first, so "*.go" matches "cmd/main.go".
+// Paths are cleaned the same way (backslashes, "./" prefixes). Invalid
+// patterns match nothing; use ValidateGlob to surface the error.
+//
+// Supported syntax, matched per "/"-separated segment:
+//   - "*" matches any sequence of characters except "/"
+//   - "?" matches any single character except "/"
+//   - "[abc]", "[a-z]", "[^a-z]" match one character from a class
+//   - "**" as a whole segment matches zero or more directories, so
+//     "src/**/*.go" matches "src/a.go" and "src/a/b/c.go"
+//
+// A "*" never crosses a directory boundary: "src/*.go" matches "src/a.go"
+// but not "src/a/b.go". "**" inside a segment (e.g. "a**b") behaves like
+// "*". Escaping is not supported, since backslashes are path separators.
+func MatchGlob(pattern, filePath string) bool {
+	p := NormalizeGlob(pattern)
+	if p == "" {
+		return false
+	}
+
+	name := strings.TrimPrefix(path.Clean("/"+ToPosixPath(filePath)), "/")
+	return matchSegments(strings.Split(p, "/"), strings.Split(name, "/"))
+}
+
+// matchSegments matches pattern segments against path segments, expanding
+// "**" to zero or more path segments.
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Collapse consecutive "**" and try every possible expansion.
+			for len(pattern) > 0 && pattern[0] == "**" {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 0 {
+				return true
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
@@ -0,0 +1,139 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// =============================================================================
+// Container Image References
+// =============================================================================
+
+const (
+	defaultRegistry  = "docker.io"
+	defaultNamespace = "library"
+	defaultTag       = "latest"
+)
+
+var (
+	imageTagPattern    = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+	imageDigestPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*(?:[+._-][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,}$`)
+)
+
+// ImageRef is a parsed container image reference such as
+// "registry.io/ns/app:tag@sha256:...".
+type ImageRef struct {
+	Registry   string `json:"registry,omitempty"` // e.g. "ghcr.io"; empty means Docker Hub
+	Repository string `json:"repository"`         // e.g. "ns/app"
+	Tag        string `json:"tag,omitempty"`      // e.g. "1.25"
+	Digest     string `json:"digest,omitempty"`   // e.g. "sha256:..."
+}
+
+// ParseImageRef splits an image reference into registry, repository, tag and
+// digest. Components that are not present are left empty; use Normalize to
+// fill in Docker Hub defaults.
+//
+// The first path component is treated as a registry when it contains a "."
+// or ":" or is "localhost", following Docker's rules.
+func ParseImageRef(ref string) (*ImageRef, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, fmt.Errorf("empty image reference")
+	}
+	if strings.ContainsAny(ref, " \t\n") {
+		return nil, fmt.Errorf("invalid image reference %q: contains whitespace", ref)
+	}
+
+	var img ImageRef
+
+	name, digest, hasDigest := strings.Cut(ref, "@")
+	if hasDigest {
+		if !imageDigestPattern.MatchString(digest) {
+			return nil, fmt.Errorf("invalid image reference %q: malformed digest", ref)
+		}
+		img.Digest = digest
+	}
+
+	if idx := strings.LastIndex(name, ":"); idx > strings.LastIndex(name, "/") {
+		tag := name[idx+1:]
+		if !imageTagPattern.MatchString(tag) {
+			return nil, fmt.Errorf("invalid image reference %q: malformed tag", ref)
+		}
+		name, img.Tag = name[:idx], tag
+	}
+
+	if first, rest, ok := strings.Cut(name, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		img.Registry, name = first, rest
+	}
+
+	if name == "" || strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/") || strings.Contains(name, "//") {
+		return nil, fmt.Errorf("invalid image reference %q: malformed repository", ref)
+	}
+	img.Repository = name
+
+	return &img, nil
+}
+
+// Normalize returns a copy of the reference with Docker Hub defaults filled in:
+// an empty registry becomes "docker.io", single-component Docker Hub
+// repositories gain the "library/" namespace, and "latest" is used when
+// neither a tag nor a digest is present. Registry and repository are
+// lower-cased.
+func (r ImageRef) Normalize() ImageRef {
+	n := r
+	n.Registry = strings.ToLower(n.Registry)
+	n.Repository = strings.ToLower(n.Repository)
+
+	if n.Registry == "" || n.Registry == "index.docker.io" || n.Registry == "registry-1.docker.io" {
+		n.Registry = defaultRegistry
+	}
+	if n.Registry == defaultRegistry && !strings.Contains(n.Repository, "/") {
+		n.Repository = defaultNamespace + "/" + n.Repository
+	}
+	if n.Tag == "" && n.Digest == "" {
+		n.Tag = defaultTag
+	}
+	return n
+}
+
+// Name returns the registry and repository ("docker.io/library/nginx").
+func (r ImageRef) Name() string {
+	if r.Registry == "" {
+		return r.Repository
+	}
+	return r.Registry + "/" + r.Repository
+}
+
+// String returns the reference in "registry/repository:tag@digest" form,
+// omitting empty components.
+func (r ImageRef) String() string {
+	s := r.Name()
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// normalizeImageRef rewrites an image reference into a canonical
+// "registry/repository:tag" or "registry/repository@digest" form for
+// fingerprinting.
+//
+// When both a tag and a digest are present the digest is dropped: the tag is
+// the stable identity, while the digest changes on every rebuild.
+// Unparseable references are returned trimmed and lower-cased.
+func normalizeImageRef(ref string) string {
+	img, err := ParseImageRef(ref)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(ref))
+	}
+
+	n := img.Normalize()
+	if n.Tag != "" {
+		n.Digest = ""
+	}
+	return n.String()
+}
@@ -294,6 +294,10 @@ type ParseOptions struct {
 	Branch    string `json:"branch"`
 	CommitSHA string `json:"commit_sha"`
 
+	// Scan run identifier, recorded as the report ID and in each
+	// finding's provenance
+	ScanID string `json:"scan_id"`
+
 	// Defaults
 	DefaultConfidence int `json:"default_confidence"`
 }
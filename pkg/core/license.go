@@ -0,0 +1,464 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// =============================================================================
+// License Normalization (SPDX)
+// =============================================================================
+
+// licenseAliases maps common license spellings (lower-cased, trimmed) to
+// canonical SPDX identifiers.
+var licenseAliases = map[string]string{
+	// Apache
+	"apache2":                     "Apache-2.0",
+	"apache 2":                    "Apache-2.0",
+	"apache 2.0":                  "Apache-2.0",
+	"apache-2":                    "Apache-2.0",
+	"apache-2.0":                  "Apache-2.0",
+	"apache2.0":                   "Apache-2.0",
+	"apache license 2.0":          "Apache-2.0",
+	"apache license, version 2.0": "Apache-2.0",
+	"apache license version 2.0":  "Apache-2.0",
+	"apache software license":     "Apache-2.0",
+	"the apache software license": "Apache-2.0",
+	"asl 2.0":                     "Apache-2.0",
+	"apache-1.1":                  "Apache-1.1",
+	"apache license 1.1":          "Apache-1.1",
+
+	// MIT
+	"mit":             "MIT",
+	"mit license":     "MIT",
+	"the mit license": "MIT",
+	"expat":           "MIT",
+
+	// BSD
+	"bsd":             "BSD-3-Clause",
+	"bsd-2":           "BSD-2-Clause",
+	"bsd-2-clause":    "BSD-2-Clause",
+	"bsd 2-clause":    "BSD-2-Clause",
+	"simplified bsd":  "BSD-2-Clause",
+	"freebsd":         "BSD-2-Clause",
+	"bsd-3":           "BSD-3-Clause",
+	"bsd-3-clause":    "BSD-3-Clause",
+	"bsd 3-clause":    "BSD-3-Clause",
+	"new bsd":         "BSD-3-Clause",
+	"new bsd license": "BSD-3-Clause",
+	"modified bsd":    "BSD-3-Clause",
+	"revised bsd":     "BSD-3-Clause",
+	"bsd license":     "BSD-3-Clause",
+
+	// GPL family
+	"gpl-2.0":           "GPL-2.0-only",
+	"gpl-2.0-only":      "GPL-2.0-only",
+	"gplv2":             "GPL-2.0-only",
+	"gpl v2":            "GPL-2.0-only",
+	"gpl-2.0+":          "GPL-2.0-or-later",
+	"gpl-2.0-or-later":  "GPL-2.0-or-later",
+	"gpl-3.0":           "GPL-3.0-only",
+	"gpl-3.0-only":      "GPL-3.0-only",
+	"gplv3":             "GPL-3.0-only",
+	"gpl v3":            "GPL-3.0-only",
+	"gpl-3.0+":          "GPL-3.0-or-later",
+	"gpl-3.0-or-later":  "GPL-3.0-or-later",
+	"lgpl-2.1":          "LGPL-2.1-only",
+	"lgpl-2.1-only":     "LGPL-2.1-only",
+	"lgplv2.1":          "LGPL-2.1-only",
+	"lgpl-2.1+":         "LGPL-2.1-or-later",
+	"lgpl-2.1-or-later": "LGPL-2.1-or-later",
+	"lgpl-3.0":          "LGPL-3.0-only",
+	"lgpl-3.0-only":     "LGPL-3.0-only",
+	"lgplv3":            "LGPL-3.0-only",
+	"lgpl-3.0+":         "LGPL-3.0-or-later",
+	"lgpl-3.0-or-later": "LGPL-3.0-or-later",
+	"agpl-3.0":          "AGPL-3.0-only",
+	"agpl-3.0-only":     "AGPL-3.0-only",
+	"agplv3":            "AGPL-3.0-only",
+	"agpl-3.0-or-later": "AGPL-3.0-or-later",
+
+	// Other common licenses
+	"mpl-2.0":                    "MPL-2.0",
+	"mpl 2.0":                    "MPL-2.0",
+	"mozilla public license 2.0": "MPL-2.0",
+	"isc":                        "ISC",
+	"isc license":                "ISC",
+	"unlicense":                  "Unlicense",
+	"the unlicense":              "Unlicense",
+	"cc0-1.0":                    "CC0-1.0",
+	"cc0":                        "CC0-1.0",
+	"epl-1.0":                    "EPL-1.0",
+	"epl-2.0":                    "EPL-2.0",
+	"eclipse public license 2.0": "EPL-2.0",
+	"zlib":                       "Zlib",
+	"0bsd":                       "0BSD",
+	"python-2.0":                 "Python-2.0",
+	"psf":                        "PSF-2.0",
+	"psf-2.0":                    "PSF-2.0",
+	"wtfpl":                      "WTFPL",
+	"artistic-2.0":               "Artistic-2.0",
+	"bsl-1.0":                    "BSL-1.0",
+	"boost software license 1.0": "BSL-1.0",
+}
+
+// NormalizeLicense maps a raw license string to its canonical SPDX identifier.
+// SPDX expressions (e.g. "mit OR apache2") are normalized operand by operand,
+// with operators upper-cased; an operand may span several words, as in
+// "Apache License 2.0 OR MIT". Unknown licenses are returned trimmed but
+// otherwise unchanged.
+func NormalizeLicense(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+
+	// Whole-string lookup first: some aliases contain spaces or commas
+	if canonical, ok := licenseAliases[strings.ToLower(raw)]; ok {
+		return canonical
+	}
+
+	tokens := tokenizeLicenseExpression(raw)
+	if len(tokens) <= 1 {
+		// A single operand, possibly with irregular spacing
+		if len(tokens) == 1 {
+			if canonical, ok := licenseAliases[strings.ToLower(tokens[0])]; ok {
+				return canonical
+			}
+		}
+		return raw
+	}
+
+	parts := make([]string, 0, len(tokens))
+	for _, tok := range tokens {
+		switch strings.ToUpper(tok) {
+		case "OR", "AND", "WITH":
+			parts = append(parts, strings.ToUpper(tok))
+		case "(", ")":
+			parts = append(parts, tok)
+		default:
+			if canonical, ok := licenseAliases[strings.ToLower(tok)]; ok {
+				parts = append(parts, canonical)
+			} else {
+				parts = append(parts, tok)
+			}
+		}
+	}
+
+	return strings.ReplaceAll(strings.ReplaceAll(strings.Join(parts, " "), "( ", "("), " )", ")")
+}
+
+// IsLicenseAllowed reports whether a license (or SPDX expression) satisfies
+// the allowlist. Both sides are normalized before comparison.
+//
+// Expression semantics:
+//   - "A OR B" is allowed if either A or B is allowed (the consumer may choose)
+//   - "A AND B" is allowed only if both A and B are allowed
+//   - "A WITH exception" is allowed if A is allowed
+//
+// Parentheses are supported. An empty license, or a malformed expression
+// (see ValidateLicenseExpression), is never allowed.
+func IsLicenseAllowed(license string, allowlist []string) bool {
+	if strings.TrimSpace(license) == "" {
+		return false
+	}
+
+	allowed := make(map[string]bool, len(allowlist))
+	for _, l := range allowlist {
+		allowed[strings.ToLower(NormalizeLicense(l))] = true
+	}
+
+	// Whole-string match handles aliases that would otherwise tokenize apart
+	if allowed[strings.ToLower(NormalizeLicense(license))] {
+		return true
+	}
+
+	p := &licenseExprParser{tokens: tokenizeLicenseExpression(license), allowed: allowed}
+	result := p.parseOr()
+	return result && p.err == nil && p.pos == len(p.tokens)
+}
+
+// ValidateLicenseExpression reports whether expr is a well-formed license
+// expression, e.g. that every WITH is followed by an exception and
+// parentheses are balanced.
+func ValidateLicenseExpression(expr string) error {
+	p := &licenseExprParser{tokens: tokenizeLicenseExpression(expr)}
+	p.parseOr()
+	if p.err != nil {
+		return fmt.Errorf("invalid license expression %q: %w", expr, p.err)
+	}
+	if p.pos != len(p.tokens) {
+		return fmt.Errorf("invalid license expression %q: unexpected %q", expr, p.tokens[p.pos])
+	}
+	return nil
+}
+
+// tokenizeLicenseExpression splits an SPDX expression into operands,
+// operators and parentheses. Only OR, AND, WITH (case-insensitive) and
+// parentheses separate operands, so a multi-word license name such as
+// "Apache License 2.0" stays one operand, its words joined by single spaces.
+func tokenizeLicenseExpression(expr string) []string {
+	var tokens, operand []string
+
+	flush := func() {
+		if len(operand) > 0 {
+			tokens = append(tokens, strings.Join(operand, " "))
+			operand = operand[:0]
+		}
+	}
+
+	for _, field := range strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expr)) {
+		switch {
+		case field == "(" || field == ")" || isLicenseOperator(field):
+			flush()
+			tokens = append(tokens, field)
+		default:
+			operand = append(operand, field)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// isLicenseOperator reports whether tok is an SPDX expression operator.
+func isLicenseOperator(tok string) bool {
+	switch strings.ToUpper(tok) {
+	case "OR", "AND", "WITH":
+		return true
+	}
+	return false
+}
+
+// licenseExprParser evaluates an SPDX expression against an allowlist.
+// Grammar (AND binds tighter than OR):
+//
+//	or   := and ("OR" and)*
+//	and  := term ("AND" term)*
+//	term := "(" or ")" | license ["WITH" exception]
+type licenseExprParser struct {
+	tokens  []string
+	pos     int
+	allowed map[string]bool
+	err     error // First syntax error
+}
+
+func (p *licenseExprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *licenseExprParser) parseOr() bool {
+	result := p.parseAnd()
+	for strings.EqualFold(p.peek(), "OR") {
+		p.pos++
+		right := p.parseAnd()
+		result = result || right
+	}
+	return result
+}
+
+func (p *licenseExprParser) parseAnd() bool {
+	result := p.parseTerm()
+	for strings.EqualFold(p.peek(), "AND") {
+		p.pos++
+		right := p.parseTerm()
+		result = result && right
+	}
+	return result
+}
+
+func (p *licenseExprParser) parseTerm() bool {
+	tok := p.peek()
+	if tok == "" {
+		return false
+	}
+	p.pos++
+
+	if tok == "(" {
+		result := p.parseOr()
+		if p.peek() == ")" {
+			p.pos++
+		} else if p.err == nil {
+			p.err = fmt.Errorf("missing closing parenthesis")
+		}
+		return result
+	}
+
+	// Exceptions don't affect whether the base license is allowed
+	if strings.EqualFold(p.peek(), "WITH") {
+		p.pos++
+		exception := p.peek()
+		if exception == "" || exception == "(" || exception == ")" || isLicenseOperator(exception) {
+			if p.err == nil {
+				p.err = fmt.Errorf("missing exception after WITH")
+			}
+			return false
+		}
+		p.pos++
+	}
+
+	return p.allowed[strings.ToLower(NormalizeLicense(tok))]
+}
+
+// =============================================================================
+// Manifest License Extraction
+// =============================================================================
+
+// ExtractLicenses reads declared licenses from manifest content and returns
+// them by package name, normalized with NormalizeLicense. Supported:
+//   - npm: package.json ("license", or legacy "licenses") and the
+//     "packages" of package-lock.json (v2+)
+//   - composer: composer.json and the "packages" of composer.lock, where
+//     several licenses become an "OR" expression
+//   - cargo: the [package] table of Cargo.toml
+//   - pip: the [project] (PEP 621) or [tool.poetry] table of pyproject.toml,
+//     with license as a string or { text = "..." }
+//
+// Packages without a declared license are omitted. TOML manifests are read
+// with a minimal line-based reader that handles the single-line forms above.
+// Other package types return an error.
+func ExtractLicenses(pkgType PackageType, manifestContent []byte) (map[string]string, error) {
+	licenses := make(map[string]string)
+	add := func(name, license string) {
+		if name != "" && strings.TrimSpace(license) != "" {
+			licenses[name] = NormalizeLicense(license)
+		}
+	}
+
+	switch pkgType {
+	case PackageTypeNPM, PackageTypeComposer:
+		if err := extractJSONLicenses(manifestContent, add); err != nil {
+			return nil, fmt.Errorf("failed to parse %s manifest: %w", pkgType, err)
+		}
+	case PackageTypeCargo:
+		add(tomlManifestLicense(manifestContent, "package"))
+	case PackageTypePyPI:
+		add(tomlManifestLicense(manifestContent, "project", "tool.poetry"))
+	default:
+		return nil, fmt.Errorf("license extraction is not supported for %q manifests", pkgType)
+	}
+
+	return licenses, nil
+}
+
+// jsonManifest covers the license fields of package.json, package-lock.json,
+// composer.json and composer.lock.
+type jsonManifest struct {
+	Name     string          `json:"name"`
+	License  json.RawMessage `json:"license"`
+	Licenses []struct {
+		Type string `json:"type"`
+	} `json:"licenses"`
+	Packages json.RawMessage `json:"packages"`
+}
+
+// extractJSONLicenses reports the licenses of a JSON manifest or lockfile.
+func extractJSONLicenses(data []byte, add func(name, license string)) error {
+	var m jsonManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	add(m.Name, m.license())
+
+	packages := bytes.TrimSpace(m.Packages)
+	switch {
+	case len(packages) == 0:
+	case packages[0] == '{': // package-lock.json: keyed by install path
+		var entries map[string]jsonManifest
+		if err := json.Unmarshal(packages, &entries); err != nil {
+			return err
+		}
+		for path, entry := range entries {
+			name := entry.Name
+			if idx := strings.LastIndex(path, "node_modules/"); idx >= 0 {
+				name = path[idx+len("node_modules/"):]
+			}
+			add(name, entry.license())
+		}
+	case packages[0] == '[': // composer.lock
+		var entries []jsonManifest
+		if err := json.Unmarshal(packages, &entries); err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			add(entry.Name, entry.license())
+		}
+	}
+	return nil
+}
+
+// license returns the declared license: a string, an array of strings
+// (joined with OR), a {"type": ...} object, or the legacy "licenses" list.
+func (m jsonManifest) license() string {
+	var s string
+	if json.Unmarshal(m.License, &s) == nil && s != "" {
+		return s
+	}
+	var list []string
+	if json.Unmarshal(m.License, &list) == nil && len(list) > 0 {
+		return strings.Join(list, " OR ")
+	}
+	var obj struct {
+		Type string `json:"type"`
+	}
+	if json.Unmarshal(m.License, &obj) == nil && obj.Type != "" {
+		return obj.Type
+	}
+
+	types := make([]string, 0, len(m.Licenses))
+	for _, l := range m.Licenses {
+		if l.Type != "" {
+			types = append(types, l.Type)
+		}
+	}
+	return strings.Join(types, " OR ")
+}
+
+// tomlManifestLicense returns the name and license declared in the first of
+// tables that has a name.
+func tomlManifestLicense(data []byte, tables ...string) (name, license string) {
+	values := make(map[string]map[string]string)
+	current := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			current = strings.TrimSpace(strings.Trim(line, "[]"))
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if key != "name" && key != "license" {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if inner, ok := strings.CutPrefix(value, "{"); ok {
+			// Inline table: license = { text = "MIT" }
+			value = ""
+			for _, field := range strings.Split(strings.TrimSuffix(inner, "}"), ",") {
+				k, v, _ := strings.Cut(field, "=")
+				if strings.TrimSpace(k) == "text" {
+					value = strings.TrimSpace(v)
+				}
+			}
+		}
+		if values[current] == nil {
+			values[current] = make(map[string]string)
+		}
+		values[current][key] = strings.Trim(value, `"'`)
+	}
+
+	for _, table := range tables {
+		if v := values[table]; v["name"] != "" {
+			return v["name"], v["license"]
+		}
+	}
+	return "", ""
+}
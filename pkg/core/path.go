@@ -0,0 +1,51 @@
+package core
+
+import (
+	"path"
+	"strings"
+
+	"github.com/rediverio/sdk/pkg/ris"
+)
+
+// =============================================================================
+// Finding Paths
+// =============================================================================
+
+// ToPosixPath converts Windows "\" separators in p to "/". Nothing else is
+// changed; use NormalizeFindingPath for the canonical form of finding paths.
+func ToPosixPath(p string) string {
+	return strings.ReplaceAll(p, `\`, "/")
+}
+
+// NormalizeFindingPath returns the canonical, OS-independent form of a
+// finding path: "/" separators, cleaned of "." and ".." elements and
+// repeated separators, without a leading "./". A path scanned on Windows
+// ("src\app\main.go") and on Linux ("./src/app/main.go") normalize to the
+// same "src/app/main.go". An empty path stays empty.
+func NormalizeFindingPath(p string) string {
+	if p == "" {
+		return ""
+	}
+	return path.Clean(ToPosixPath(p))
+}
+
+// NormalizeFindingPaths applies NormalizeFindingPath to the location and
+// data flow paths of findings in place, so findings from scans on different
+// operating systems group and compare alike. Fingerprints generated by
+// pkg/shared/fingerprint already fold separators.
+func NormalizeFindingPaths(findings []ris.Finding) {
+	for i := range findings {
+		f := &findings[i]
+		if f.Location != nil {
+			f.Location.Path = NormalizeFindingPath(f.Location.Path)
+		}
+		if f.DataFlow == nil {
+			continue
+		}
+		for _, locs := range [][]ris.DataFlowLocation{f.DataFlow.Sources, f.DataFlow.Intermediates, f.DataFlow.Sinks} {
+			for j := range locs {
+				locs[j].Path = NormalizeFindingPath(locs[j].Path)
+			}
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"math"
+
+	"github.com/rediverio/sdk/pkg/ris"
+)
+
+// =============================================================================
+// Risk Prioritization (CVSS + EPSS)
+// =============================================================================
+
+// RiskWeights controls how much raw severity (CVSS) and exploit probability
+// (EPSS) contribute to a combined priority score.
+type RiskWeights struct {
+	CVSSWeight float64 `yaml:"cvss_weight" json:"cvss_weight"`
+	EPSSWeight float64 `yaml:"epss_weight" json:"epss_weight"`
+}
+
+// DefaultRiskWeights returns the default weighting: 60% CVSS, 40% EPSS.
+// Severity stays the primary signal, while a high exploit probability can
+// still lift a medium-severity vuln above an unexploited high.
+func DefaultRiskWeights() RiskWeights {
+	return RiskWeights{
+		CVSSWeight: 0.6,
+		EPSSWeight: 0.4,
+	}
+}
+
+// Normalize returns weights scaled to sum to 1. Negative weights are treated
+// as zero; if both weights are zero the defaults are returned.
+func (w RiskWeights) Normalize() RiskWeights {
+	c, e := math.Max(w.CVSSWeight, 0), math.Max(w.EPSSWeight, 0)
+	sum := c + e
+	if sum == 0 {
+		return DefaultRiskWeights()
+	}
+	return RiskWeights{CVSSWeight: c / sum, EPSSWeight: e / sum}
+}
+
+// CombinedPriority blends a CVSS score (0-10) and an EPSS probability (0-1)
+// into a 0-10 priority score using DefaultRiskWeights.
+func CombinedPriority(cvss, epss float64) float64 {
+	return CombinedPriorityWeighted(cvss, epss, DefaultRiskWeights())
+}
+
+// CombinedPriorityWeighted blends a CVSS score (0-10) and an EPSS probability
+// (0-1) into a 0-10 priority score. Weights are normalized (see
+// RiskWeights.Normalize), so {3, 1} is equivalent to {0.75, 0.25}.
+// Inputs outside their ranges are clamped.
+func CombinedPriorityWeighted(cvss, epss float64, weights RiskWeights) float64 {
+	w := weights.Normalize()
+	cvss = math.Min(math.Max(cvss, 0), 10)
+	epss = math.Min(math.Max(epss, 0), 1)
+
+	score := w.CVSSWeight*cvss + w.EPSSWeight*epss*10
+	return math.Round(score*10) / 10
+}
+
+// =============================================================================
+// Remediation Effort
+// =============================================================================
+
+// EffortLevel is a rough estimate of the work needed to remediate a finding.
+type EffortLevel string
+
+const (
+	EffortLow    EffortLevel = "low"
+	EffortMedium EffortLevel = "medium"
+	EffortHigh   EffortLevel = "high"
+)
+
+// deepDependencyDepth is the dependency depth from which a transitive
+// dependency counts as deep in the tree.
+const deepDependencyDepth = 3
+
+// RemediationEffort estimates the effort to fix a finding, for plotting
+// effort against risk (see CombinedPriority) in reports.
+//
+// For dependency findings the heuristic is:
+//   - no fixed version: High, since the package must be replaced, patched
+//     or mitigated
+//   - direct dependency with a fix: Low, a version bump in the manifest
+//   - transitive dependency at depth 2 with a fix, or of unknown depth:
+//     Medium, usually a bump of the direct parent or a version override
+//   - transitive dependency at depth 3 or more with a fix: High, as the fix
+//     has to propagate through several intermediate packages
+//
+// Other findings (code, secrets, misconfigurations) are Low when a fix is
+// available (see Finding.HasFix, e.g. an autofix) and Medium otherwise.
+func RemediationEffort(f ris.Finding) EffortLevel {
+	v := f.Vulnerability
+	if v == nil || v.Package == "" {
+		if f.HasFix() {
+			return EffortLow
+		}
+		return EffortMedium
+	}
+
+	switch {
+	case !f.HasFix():
+		return EffortHigh
+	case f.IsDirectDependency():
+		return EffortLow
+	case v.DependencyDepth >= deepDependencyDepth:
+		return EffortHigh
+	default:
+		return EffortMedium
+	}
+}
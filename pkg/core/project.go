@@ -0,0 +1,102 @@
+package core
+
+import (
+	"path"
+	"sort"
+)
+
+// =============================================================================
+// Project Inference
+// =============================================================================
+
+// Project is a logical project in a (mono)repository, inferred from the
+// location of its dependency manifests.
+type Project struct {
+	// Root is the project directory, slash-separated; "." for the top level.
+	Root string `yaml:"root" json:"root"`
+
+	// Manifests are the manifest and lockfile paths of the project, sorted.
+	Manifests []string `yaml:"manifests" json:"manifests"`
+
+	// PackageTypes are the ecosystems detected from the manifests, in
+	// DetectPackageType order.
+	PackageTypes []PackageType `yaml:"package_types" json:"package_types"`
+}
+
+// InferProjects groups manifest paths into projects so findings can be
+// attributed to sub-projects of a monorepo. Manifests in the same directory
+// form one project. A directory holding only lockfiles (see IsLockfile) is
+// not a project of its own; its lockfiles join the project of the nearest
+// ancestor directory, if any. Paths DetectPackageType does not recognize are
+// ignored. Projects are returned sorted by root.
+func InferProjects(manifestPaths []string) []Project {
+	byDir := make(map[string]*Project)
+	hasManifest := make(map[string]bool)
+
+	for _, p := range manifestPaths {
+		clean := NormalizeFindingPath(p)
+		if DetectPackageType(path.Base(clean)) == "" {
+			continue
+		}
+		dir := path.Dir(clean)
+		proj, ok := byDir[dir]
+		if !ok {
+			proj = &Project{Root: dir}
+			byDir[dir] = proj
+		}
+		proj.Manifests = append(proj.Manifests, clean)
+		if !IsLockfile(clean) {
+			hasManifest[dir] = true
+		}
+	}
+
+	// Fold lockfile-only directories into their nearest ancestor project.
+	for dir, proj := range byDir {
+		if hasManifest[dir] {
+			continue
+		}
+		if parent := nearestProjectDir(dir, hasManifest); parent != "" {
+			byDir[parent].Manifests = append(byDir[parent].Manifests, proj.Manifests...)
+			delete(byDir, dir)
+		}
+	}
+
+	projects := make([]Project, 0, len(byDir))
+	for _, proj := range byDir {
+		sort.Strings(proj.Manifests)
+		proj.PackageTypes = projectPackageTypes(proj.Manifests)
+		projects = append(projects, *proj)
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].Root < projects[j].Root
+	})
+	return projects
+}
+
+// nearestProjectDir returns the closest ancestor of dir that has a manifest,
+// or "" if there is none.
+func nearestProjectDir(dir string, hasManifest map[string]bool) string {
+	for dir != "." && dir != "/" {
+		dir = path.Dir(dir)
+		if hasManifest[dir] {
+			return dir
+		}
+	}
+	return ""
+}
+
+// projectPackageTypes returns the distinct package types of manifests, in
+// DetectPackageType order.
+func projectPackageTypes(manifests []string) []PackageType {
+	found := make(map[PackageType]bool)
+	for _, m := range manifests {
+		found[DetectPackageType(path.Base(m))] = true
+	}
+	var types []PackageType
+	for _, entry := range manifestPatterns {
+		if found[entry.pkgType] {
+			types = append(types, entry.pkgType)
+		}
+	}
+	return types
+}
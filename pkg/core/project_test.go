@@ -0,0 +1,18 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInferProjects_KeepsLockfiles(t *testing.T) {
+	projects := InferProjects([]string{"a/package.json", "a/pnpm-lock.yaml", "b/poetry.lock", "b/pyproject.toml"})
+
+	want := []Project{
+		{Root: "a", Manifests: []string{"a/package.json", "a/pnpm-lock.yaml"}, PackageTypes: []PackageType{PackageTypeNPM}},
+		{Root: "b", Manifests: []string{"b/poetry.lock", "b/pyproject.toml"}, PackageTypes: []PackageType{PackageTypePyPI}},
+	}
+	if !reflect.DeepEqual(projects, want) {
+		t.Errorf("InferProjects = %+v, want %+v", projects, want)
+	}
+}
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/rediverio/sdk/pkg/ris"
+	"github.com/rediverio/sdk/pkg/shared/severity"
+)
+
+// =============================================================================
+// Quality Gate
+// =============================================================================
+
+// QualityGate defines CI pass/fail thresholds on finding counts,
+// e.g. "fail if any critical, or more than 5 high".
+//
+// Each Max* field is the maximum number of findings allowed at that severity;
+// a negative value disables the check. Note that the zero value allows none.
+// FailOnAny fails the gate if any finding is at or above that severity; empty
+// (or unrecognized) disables it.
+type QualityGate struct {
+	MaxCritical int          `yaml:"max_critical" json:"max_critical"`
+	MaxHigh     int          `yaml:"max_high" json:"max_high"`
+	MaxMedium   int          `yaml:"max_medium" json:"max_medium"`
+	FailOnAny   ris.Severity `yaml:"fail_on_any" json:"fail_on_any,omitempty"`
+}
+
+// Evaluate checks findings against the gate. It returns whether the gate
+// passed and, if not, one human-readable reason per breached threshold.
+func (g QualityGate) Evaluate(findings []ris.Finding) (passed bool, reasons []string) {
+	counts := CountSeverities(findings)
+
+	if threshold := severity.FromString(string(g.FailOnAny)); threshold != severity.Unknown {
+		atOrAbove := 0
+		for _, level := range severity.AllLevels() {
+			if level != severity.Unknown && level.IsAtLeast(threshold) {
+				atOrAbove += countForLevel(counts, level)
+			}
+		}
+		if atOrAbove > 0 {
+			reasons = append(reasons, fmt.Sprintf("%d finding(s) at or above %s severity (fail on any)", atOrAbove, threshold))
+		}
+	}
+
+	limits := []struct {
+		level severity.Level
+		max   int
+	}{
+		{severity.Critical, g.MaxCritical},
+		{severity.High, g.MaxHigh},
+		{severity.Medium, g.MaxMedium},
+	}
+	for _, l := range limits {
+		if n := countForLevel(counts, l.level); l.max >= 0 && n > l.max {
+			reasons = append(reasons, fmt.Sprintf("%d %s finding(s) exceed the maximum of %d", n, l.level, l.max))
+		}
+	}
+
+	return len(reasons) == 0, reasons
+}
+
+// countForLevel returns the count for a single severity level.
+func countForLevel(counts SeverityCounts, level severity.Level) int {
+	switch level {
+	case severity.Critical:
+		return counts.Critical
+	case severity.High:
+		return counts.High
+	case severity.Medium:
+		return counts.Medium
+	case severity.Low:
+		return counts.Low
+	case severity.Info:
+		return counts.Info
+	default:
+		return counts.Unknown
+	}
+}
+
+// =============================================================================
+// CLI Exit Codes
+// =============================================================================
+
+// Process exit codes for CLI consumers. Every scanner wrapper uses the same
+// contract so CI pipelines can tell "findings" apart from "the scan broke".
+const (
+	// ExitCodeOK means the scan completed with no findings at or above the
+	// failure threshold.
+	ExitCodeOK = 0
+	// ExitCodeFindings means at least one finding met the failure threshold.
+	ExitCodeFindings = 1
+	// ExitCodeToolError means the scan itself failed (scanner crashed, output
+	// could not be parsed, ...); results are incomplete and must not be
+	// treated as a pass.
+	ExitCodeToolError = 2
+)
+
+// ExitCodeForFindings returns ExitCodeFindings if any finding's severity is
+// at or above failOn, and ExitCodeOK otherwise. Findings with unrecognized
+// severities never fail the run; an unrecognized failOn disables the check.
+// Callers return ExitCodeToolError themselves when the scan fails.
+func ExitCodeForFindings(findings []ris.Finding, failOn ris.Severity) int {
+	threshold := severity.FromString(string(failOn))
+	if threshold == severity.Unknown {
+		return ExitCodeOK
+	}
+
+	for i := range findings {
+		level := severity.FromString(string(findings[i].Severity))
+		if level != severity.Unknown && level.IsAtLeast(threshold) {
+			return ExitCodeFindings
+		}
+	}
+	return ExitCodeOK
+}
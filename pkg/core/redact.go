@@ -0,0 +1,148 @@
+package core
+
+import (
+	"path"
+	"strings"
+
+	"github.com/rediverio/sdk/pkg/ris"
+)
+
+// =============================================================================
+// Finding Redaction
+// =============================================================================
+
+// redactedSnippet replaces snippets of secret findings without a masked value.
+const redactedSnippet = "[REDACTED]"
+
+// RedactOptions controls what RedactFinding strips. The zero value strips
+// nothing; DefaultRedactOptions enables everything.
+type RedactOptions struct {
+	// MaskSecrets replaces the code snippets of secret findings, which
+	// contain the raw secret, with Secret.MaskedValue (or "[REDACTED]").
+	MaskSecrets bool `yaml:"mask_secrets" json:"mask_secrets"`
+
+	// RelativePaths rewrites absolute paths to be relative to RepoRoot.
+	// Absolute paths outside RepoRoot (or any absolute path when RepoRoot is
+	// empty) are reduced to their file name.
+	RelativePaths bool   `yaml:"relative_paths" json:"relative_paths"`
+	RepoRoot      string `yaml:"repo_root" json:"repo_root"`
+
+	// StripProvenance removes the scanner and scan run details.
+	StripProvenance bool `yaml:"strip_provenance" json:"strip_provenance"`
+
+	// StripAuthor removes the git author name and email.
+	StripAuthor bool `yaml:"strip_author" json:"strip_author"`
+}
+
+// DefaultRedactOptions returns options that strip everything RedactFinding
+// supports, for sending findings to third-party systems.
+func DefaultRedactOptions(repoRoot string) RedactOptions {
+	return RedactOptions{
+		MaskSecrets:     true,
+		RelativePaths:   true,
+		RepoRoot:        repoRoot,
+		StripProvenance: true,
+		StripAuthor:     true,
+	}
+}
+
+// RedactFinding returns a copy of f with sensitive data removed according to
+// opts, e.g. before sending it to an external ticketing system. f itself is
+// not modified: its location and data flow are copied before being changed.
+func RedactFinding(f ris.Finding, opts RedactOptions) ris.Finding {
+	if f.Location != nil {
+		loc := *f.Location
+		f.Location = &loc
+	}
+	if f.DataFlow != nil {
+		df := ris.DataFlow{
+			Sources:       append([]ris.DataFlowLocation(nil), f.DataFlow.Sources...),
+			Intermediates: append([]ris.DataFlowLocation(nil), f.DataFlow.Intermediates...),
+			Sinks:         append([]ris.DataFlowLocation(nil), f.DataFlow.Sinks...),
+		}
+		f.DataFlow = &df
+	}
+
+	if opts.MaskSecrets && (f.Type == ris.FindingTypeSecret || f.Secret != nil) {
+		masked := redactedSnippet
+		if f.Secret != nil && f.Secret.MaskedValue != "" {
+			masked = f.Secret.MaskedValue
+		}
+		if f.Location != nil {
+			if f.Location.Snippet != "" {
+				f.Location.Snippet = masked
+			}
+			if f.Location.ContextSnippet != "" {
+				f.Location.ContextSnippet = masked
+			}
+		}
+		forEachDataFlowLocation(f.DataFlow, func(l *ris.DataFlowLocation) {
+			if l.Content != "" {
+				l.Content = masked
+			}
+		})
+	}
+
+	if opts.RelativePaths {
+		if f.Location != nil {
+			f.Location.Path = redactPath(f.Location.Path, opts.RepoRoot)
+		}
+		forEachDataFlowLocation(f.DataFlow, func(l *ris.DataFlowLocation) {
+			l.Path = redactPath(l.Path, opts.RepoRoot)
+		})
+	}
+
+	if opts.StripProvenance {
+		f.Provenance = nil
+	}
+
+	if opts.StripAuthor {
+		f.Author = ""
+		f.AuthorEmail = ""
+	}
+
+	return f
+}
+
+// forEachDataFlowLocation calls fn for every location of a data flow.
+func forEachDataFlowLocation(df *ris.DataFlow, fn func(*ris.DataFlowLocation)) {
+	if df == nil {
+		return
+	}
+	for _, locs := range [][]ris.DataFlowLocation{df.Sources, df.Intermediates, df.Sinks} {
+		for i := range locs {
+			fn(&locs[i])
+		}
+	}
+}
+
+// redactPath rewrites an absolute path relative to root, or to its file name
+// if it lies outside root. Relative paths are returned unchanged. Both Unix
+// and Windows (drive letter) paths are recognized.
+func redactPath(p, root string) string {
+	if !isAbsPath(p) {
+		return p
+	}
+
+	slashed := path.Clean(ToPosixPath(p))
+	if root != "" && isAbsPath(root) {
+		base := strings.TrimSuffix(path.Clean(ToPosixPath(root)), "/")
+		if len(slashed) > len(base)+1 && slashed[len(base)] == '/' {
+			prefix := slashed[:len(base)]
+			// Windows paths are case-insensitive
+			if prefix == base || (base[0] != '/' && strings.EqualFold(prefix, base)) {
+				return slashed[len(base)+1:]
+			}
+		}
+	}
+	return path.Base(slashed)
+}
+
+// isAbsPath reports whether p is an absolute Unix or Windows path.
+func isAbsPath(p string) bool {
+	if strings.HasPrefix(p, "/") || strings.HasPrefix(p, `\`) {
+		return true
+	}
+	return len(p) >= 3 && p[1] == ':' && (p[2] == '\\' || p[2] == '/') &&
+		(p[0] >= 'a' && p[0] <= 'z' || p[0] >= 'A' && p[0] <= 'Z')
+}
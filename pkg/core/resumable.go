@@ -0,0 +1,143 @@
+package core
+
+import (
+	"sync"
+
+	"github.com/rediverio/sdk/pkg/ris"
+)
+
+// =============================================================================
+// Resumable Stream - Retry-safe streaming of scanner output
+// =============================================================================
+
+// ResumableStream makes streamed scanner output safe to retry. It tracks how
+// much stdout has been consumed across attempts and deduplicates emitted
+// findings by fingerprint, so a caller never sees the same finding twice when
+// a flaky scan is retried.
+//
+// Example:
+//
+//	rs := core.NewResumableStream(true)
+//	emit := rs.Wrap(onFinding)
+//	for attempt := 0; attempt < 3; attempt++ {
+//	    rs.NextAttempt()
+//	    result, err := core.StreamScanner(ctx, cfg, rs.WrapOutput(func(line string, isError bool) {
+//	        if f, ok := parseLine(line); ok {
+//	            emit(f)
+//	        }
+//	    }))
+//	    if err == nil && result.ExitCode == 0 {
+//	        break
+//	    }
+//	}
+type ResumableStream struct {
+	// skipConsumed skips stdout lines already consumed by a previous attempt.
+	// Only valid when the scanner produces output in a deterministic order.
+	skipConsumed bool
+
+	mu       sync.Mutex
+	seen     map[string]struct{}
+	attempts int
+
+	// Consumed totals across all attempts (high-water mark)
+	lines int64
+	bytes int64
+
+	// Position within the current attempt
+	attemptLines int64
+}
+
+// NewResumableStream creates a new resumable stream. If skipConsumed is true,
+// stdout lines already consumed by an earlier attempt are not re-delivered on
+// retry; only enable this for scanners with deterministic output ordering.
+// Fingerprint deduplication applies either way.
+func NewResumableStream(skipConsumed bool) *ResumableStream {
+	return &ResumableStream{
+		skipConsumed: skipConsumed,
+		seen:         make(map[string]struct{}),
+	}
+}
+
+// NextAttempt marks the start of a new (or first) scanner run.
+func (rs *ResumableStream) NextAttempt() {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.attempts++
+	rs.attemptLines = 0
+}
+
+// Attempts returns the number of attempts started.
+func (rs *ResumableStream) Attempts() int {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.attempts
+}
+
+// Offset returns the number of stdout lines and bytes consumed so far, for
+// scanners that support resuming from a position (e.g. a --resume flag).
+func (rs *ResumableStream) Offset() (lines, bytes int64) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.lines, rs.bytes
+}
+
+// WrapOutput returns an OutputHandler that tracks consumed stdout and, when
+// skipConsumed is enabled, drops stdout lines delivered by a previous attempt.
+// Stderr lines are always passed through.
+func (rs *ResumableStream) WrapOutput(handler OutputHandler) OutputHandler {
+	return func(line string, isError bool) {
+		if isError {
+			if handler != nil {
+				handler(line, isError)
+			}
+			return
+		}
+
+		rs.mu.Lock()
+		rs.attemptLines++
+		replayed := rs.attemptLines <= rs.lines
+		if !replayed {
+			rs.lines = rs.attemptLines
+			rs.bytes += int64(len(line)) + 1 // Include the newline
+		}
+		skip := replayed && rs.skipConsumed
+		rs.mu.Unlock()
+
+		if !skip && handler != nil {
+			handler(line, isError)
+		}
+	}
+}
+
+// Emit records a finding and reports whether it is new. Findings without a
+// fingerprint cannot be deduplicated and are always reported as new.
+func (rs *ResumableStream) Emit(finding ris.Finding) bool {
+	if finding.Fingerprint == "" {
+		return true
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if _, ok := rs.seen[finding.Fingerprint]; ok {
+		return false
+	}
+	rs.seen[finding.Fingerprint] = struct{}{}
+	return true
+}
+
+// Wrap returns a callback that passes each finding to next only the first
+// time its fingerprint is seen, across all attempts.
+func (rs *ResumableStream) Wrap(next FindingCallback) FindingCallback {
+	return func(finding ris.Finding) {
+		if rs.Emit(finding) && next != nil {
+			next(finding)
+		}
+	}
+}
+
+// Seen returns the number of distinct fingerprints emitted.
+func (rs *ResumableStream) Seen() int {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return len(rs.seen)
+}
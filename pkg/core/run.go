@@ -0,0 +1,148 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/rediverio/sdk/pkg/ris"
+)
+
+// =============================================================================
+// Parallel Scanner Orchestration
+// =============================================================================
+
+// ScanJob is one scanner run for RunScanners.
+type ScanJob struct {
+	// Name identifies the job in the summary. Defaults to Exec.Binary.
+	Name string
+
+	// Exec configures the scanner process.
+	Exec *ExecConfig
+
+	// Parser converts the scanner's stdout to findings. If nil, the job is
+	// only executed.
+	Parser       Parser
+	ParseOptions *ParseOptions
+}
+
+// ScanJobResult is the outcome of one ScanJob.
+type ScanJobResult struct {
+	Name     string
+	Result   *ExecResult
+	Findings []ris.Finding
+
+	// Err is the execution or parse error of the job, if any. A non-zero
+	// exit code alone is not an error, since many scanners exit 1 when they
+	// report findings.
+	Err error
+}
+
+// ScanSummary aggregates the results of RunScanners.
+type ScanSummary struct {
+	// Jobs holds one result per ScanJob, in input order.
+	Jobs []ScanJobResult
+
+	// Findings are the findings of all jobs, in job order.
+	Findings []ris.Finding
+
+	// Counts are the severity counts over Findings.
+	Counts SeverityCounts
+}
+
+// Errors returns the errors of failed jobs, each prefixed with the job name.
+func (s *ScanSummary) Errors() []error {
+	var errs []error
+	for _, job := range s.Jobs {
+		if job.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", job.Name, job.Err))
+		}
+	}
+	return errs
+}
+
+// Err returns the job errors joined with errors.Join, or nil if every job
+// succeeded.
+func (s *ScanSummary) Err() error {
+	return errors.Join(s.Errors()...)
+}
+
+// RunScanners executes jobs in parallel (up to runtime.NumCPU at a time),
+// parses their output and aggregates findings and severity counts. It is the
+// one-call entry point for CLIs running several scanners.
+//
+// Failures of individual jobs are collected in their ScanJobResult (see
+// ScanSummary.Err) and do not stop other jobs. Only cancellation of ctx is
+// fatal: jobs not yet started are skipped and the partial summary is returned
+// together with ctx.Err().
+func RunScanners(ctx context.Context, jobs []ScanJob) (*ScanSummary, error) {
+	summary := &ScanSummary{Jobs: make([]ScanJobResult, len(jobs))}
+
+	sem := make(chan struct{}, runtime.NumCPU())
+	var wg sync.WaitGroup
+	for i := range jobs {
+		name := jobs[i].Name
+		if name == "" && jobs[i].Exec != nil {
+			name = jobs[i].Exec.Binary
+		}
+		summary.Jobs[i].Name = name
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			summary.Jobs[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			runScanJob(ctx, &jobs[i], &summary.Jobs[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for _, job := range summary.Jobs {
+		summary.Findings = append(summary.Findings, job.Findings...)
+	}
+	summary.Counts = CountSeverities(summary.Findings)
+
+	if err := ctx.Err(); err != nil {
+		return summary, err
+	}
+	return summary, nil
+}
+
+// runScanJob executes and parses a single job into out.
+func runScanJob(ctx context.Context, job *ScanJob, out *ScanJobResult) {
+	if job.Exec == nil {
+		out.Err = fmt.Errorf("exec config is nil")
+		return
+	}
+
+	result, err := ExecuteScanner(ctx, job.Exec)
+	out.Result = result
+	if err != nil {
+		out.Err = err
+		return
+	}
+	if result.Error != nil {
+		out.Err = result.Error
+		return
+	}
+
+	if job.Parser == nil || len(result.Stdout) == 0 {
+		return
+	}
+	report, err := job.Parser.Parse(ctx, result.Stdout, job.ParseOptions)
+	if err != nil {
+		out.Err = fmt.Errorf("failed to parse output: %w", err)
+		return
+	}
+	out.Findings = report.Findings
+}
@@ -0,0 +1,359 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rediverio/sdk/pkg/ris"
+	"github.com/rediverio/sdk/pkg/shared/severity"
+)
+
+// =============================================================================
+// Severity Thresholds
+// =============================================================================
+
+// SeverityThresholds defines the minimum CVSS score for each severity band.
+// A score at or above a threshold maps to that band; scores below Low map
+// to "info".
+type SeverityThresholds struct {
+	Critical float64 `yaml:"critical" json:"critical"`
+	High     float64 `yaml:"high" json:"high"`
+	Medium   float64 `yaml:"medium" json:"medium"`
+	Low      float64 `yaml:"low" json:"low"`
+}
+
+// DefaultSeverityThresholds returns the CVSS v3.x qualitative rating bands.
+func DefaultSeverityThresholds() SeverityThresholds {
+	return SeverityThresholds{
+		Critical: 9.0,
+		High:     7.0,
+		Medium:   4.0,
+		Low:      0.1,
+	}
+}
+
+// Severity converts a CVSS score to a severity string using these thresholds.
+func (t SeverityThresholds) Severity(score float64) string {
+	switch {
+	case score >= t.Critical:
+		return severity.Critical.String()
+	case score >= t.High:
+		return severity.High.String()
+	case score >= t.Medium:
+		return severity.Medium.String()
+	case score >= t.Low:
+		return severity.Low.String()
+	default:
+		return severity.Info.String()
+	}
+}
+
+// ResolveSeverity determines a finding's severity when both a CVSS score and a
+// scanner-provided label may be present.
+//
+// Precedence:
+//  1. If score > 0, the severity is computed from the score using thresholds.
+//     The scanner label is ignored, even if it disagrees.
+//  2. Otherwise (score is 0 or negative, i.e. not available), the label is
+//     normalized via NormalizeSeverity, honoring registered aliases.
+//  3. If neither yields a known severity, "unknown" is returned.
+//
+// A zero-value thresholds argument falls back to DefaultSeverityThresholds.
+func ResolveSeverity(score float64, label string, thresholds SeverityThresholds) string {
+	if thresholds == (SeverityThresholds{}) {
+		thresholds = DefaultSeverityThresholds()
+	}
+
+	if score > 0 {
+		return thresholds.Severity(score)
+	}
+
+	if label != "" {
+		return NormalizeSeverity(label)
+	}
+
+	return severity.Unknown.String()
+}
+
+// =============================================================================
+// CVSS Score Ranges
+// =============================================================================
+
+// ScoreRangePolicy selects the representative score of a CVSS score range.
+type ScoreRangePolicy string
+
+const (
+	ScoreRangeHigh ScoreRangePolicy = "high" // Upper bound (default, conservative)
+	ScoreRangeLow  ScoreRangePolicy = "low"  // Lower bound
+	ScoreRangeMid  ScoreRangePolicy = "mid"  // Midpoint, rounded to one decimal
+)
+
+// ParseScoreRange parses a CVSS score range such as "7.0-8.9", as reported
+// by feeds that do not commit to a single score. Spaces around the separator
+// and an en dash ("7.0–8.9") are accepted; a single score ("7.5") yields
+// low == high. Both bounds must be within 0-10 and low must not exceed high.
+func ParseScoreRange(s string) (low, high float64, err error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), "–", "-")
+	if s == "" {
+		return 0, 0, fmt.Errorf("empty score range")
+	}
+
+	lowStr, highStr, isRange := strings.Cut(s, "-")
+	if !isRange {
+		highStr = lowStr
+	}
+	if low, err = strconv.ParseFloat(strings.TrimSpace(lowStr), 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid score range %q: %w", s, err)
+	}
+	if high, err = strconv.ParseFloat(strings.TrimSpace(highStr), 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid score range %q: %w", s, err)
+	}
+
+	if math.IsNaN(low) || math.IsNaN(high) || low < 0 || high > 10 || low > high {
+		return 0, 0, fmt.Errorf("invalid score range %q: bounds must satisfy 0 <= low <= high <= 10", s)
+	}
+	return low, high, nil
+}
+
+// Score returns the representative score of a range under the policy. The
+// zero value and unknown policies use ScoreRangeHigh.
+func (p ScoreRangePolicy) Score(low, high float64) float64 {
+	switch p {
+	case ScoreRangeLow:
+		return low
+	case ScoreRangeMid:
+		return math.Round((low+high)*5) / 10
+	default:
+		return high
+	}
+}
+
+// SeverityFromScoreRange parses a score range and maps its representative
+// score under policy to a severity using DefaultSeverityThresholds.
+func SeverityFromScoreRange(s string, policy ScoreRangePolicy) (string, error) {
+	low, high, err := ParseScoreRange(s)
+	if err != nil {
+		return "", err
+	}
+	return DefaultSeverityThresholds().Severity(policy.Score(low, high)), nil
+}
+
+// =============================================================================
+// Severity Counts
+// =============================================================================
+
+// SeverityCounts holds the number of findings per severity level.
+type SeverityCounts = severity.CountBySeverity
+
+// CountSeverities counts findings by normalized severity.
+func CountSeverities(findings []ris.Finding) SeverityCounts {
+	var counts SeverityCounts
+	for _, f := range findings {
+		counts.Increment(severity.FromString(string(f.Severity)))
+	}
+	return counts
+}
+
+// SeverityDistribution returns the fraction of findings at each normalized
+// severity (e.g. {"critical": 0.1, "high": 0.4, ...}). Levels with no
+// findings are omitted; an empty input returns an empty map.
+func SeverityDistribution(findings []ris.Finding) map[string]float64 {
+	dist := make(map[string]float64)
+	if len(findings) == 0 {
+		return dist
+	}
+
+	counts := CountSeverities(findings)
+	total := float64(counts.Total)
+	for _, level := range severity.AllLevels() {
+		if n := countForLevel(counts, level); n > 0 {
+			dist[level.String()] = float64(n) / total
+		}
+	}
+	return dist
+}
+
+// IsAnomalous reports whether a severity distribution drifted from a baseline
+// by more than tolerance (an absolute fraction, e.g. 0.2) at any severity.
+// A sudden jump to 80% critical usually signals a scanner config or rule-pack
+// bug rather than a real change in risk.
+func IsAnomalous(dist, baseline map[string]float64, tolerance float64) bool {
+	for level, fraction := range dist {
+		if math.Abs(fraction-baseline[level]) > tolerance {
+			return true
+		}
+	}
+	for level, fraction := range baseline {
+		if _, ok := dist[level]; !ok && fraction > tolerance {
+			return true
+		}
+	}
+	return false
+}
+
+// SeverityDelta holds signed per-severity changes between two scans.
+// It has the same fields as SeverityCounts; values may be negative.
+type SeverityDelta SeverityCounts
+
+// DiffSeverityCounts returns after - before for each severity level and the total.
+func DiffSeverityCounts(before, after SeverityCounts) SeverityDelta {
+	return SeverityDelta{
+		Critical: after.Critical - before.Critical,
+		High:     after.High - before.High,
+		Medium:   after.Medium - before.Medium,
+		Low:      after.Low - before.Low,
+		Info:     after.Info - before.Info,
+		Unknown:  after.Unknown - before.Unknown,
+		Total:    after.Total - before.Total,
+	}
+}
+
+// String renders the non-zero deltas from most to least severe,
+// e.g. "+2 critical, -5 high". Returns "no change" if every level is zero.
+func (d SeverityDelta) String() string {
+	levels := []struct {
+		name  string
+		delta int
+	}{
+		{severity.Critical.String(), d.Critical},
+		{severity.High.String(), d.High},
+		{severity.Medium.String(), d.Medium},
+		{severity.Low.String(), d.Low},
+		{severity.Info.String(), d.Info},
+		{severity.Unknown.String(), d.Unknown},
+	}
+
+	parts := make([]string, 0, len(levels))
+	for _, l := range levels {
+		if l.delta != 0 {
+			parts = append(parts, fmt.Sprintf("%+d %s", l.delta, l.name))
+		}
+	}
+	if len(parts) == 0 {
+		return "no change"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FindingCallback is invoked for each finding as it is produced during a scan.
+type FindingCallback func(finding ris.Finding)
+
+// StreamingCounter maintains live severity counts while findings stream in.
+// It is safe for concurrent use; Snapshot may be called at any time mid-scan.
+type StreamingCounter struct {
+	mu     sync.RWMutex
+	counts SeverityCounts
+}
+
+// NewStreamingCounter creates a new streaming counter.
+func NewStreamingCounter() *StreamingCounter {
+	return &StreamingCounter{}
+}
+
+// Add records a single finding.
+func (c *StreamingCounter) Add(finding ris.Finding) {
+	level := severity.FromString(string(finding.Severity))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts.Increment(level)
+}
+
+// Wrap returns a callback that records each finding before passing it to next.
+// next may be nil, in which case findings are only counted.
+func (c *StreamingCounter) Wrap(next FindingCallback) FindingCallback {
+	return func(finding ris.Finding) {
+		c.Add(finding)
+		if next != nil {
+			next(finding)
+		}
+	}
+}
+
+// Snapshot returns a copy of the counts accumulated so far.
+func (c *StreamingCounter) Snapshot() SeverityCounts {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.counts
+}
+
+// Reset clears all accumulated counts.
+func (c *StreamingCounter) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts = SeverityCounts{}
+}
+
+// =============================================================================
+// CVSS Score Histogram
+// =============================================================================
+
+// cvssBuckets returns the bucket width in tenths of a point and the number of
+// buckets covering 0.0-10.0, or ok=false if bucketSize is not positive.
+// Widths are rounded to the nearest 0.1 (minimum 0.1), the resolution of
+// CVSS scores.
+func cvssBuckets(bucketSize float64) (width, count int, ok bool) {
+	if !(bucketSize > 0) || math.IsInf(bucketSize, 0) {
+		return 0, 0, false
+	}
+	width = max(int(math.Round(bucketSize*10)), 1)
+	count = (100 + width - 1) / width
+	return width, count, true
+}
+
+// cvssBucketLabel formats bucket i as "lo-hi" with inclusive one-decimal
+// bounds, e.g. "7.0-7.9". The last bucket always ends at 10.0.
+func cvssBucketLabel(i, width, count int) string {
+	lo := i * width
+	hi := lo + width - 1
+	if i == count-1 {
+		hi = 100
+	}
+	return fmt.Sprintf("%.1f-%.1f", float64(lo)/10, float64(hi)/10)
+}
+
+// CVSSHistogram counts CVSS scores per bucket of bucketSize points, keyed by
+// the bucket's inclusive range: with bucketSize 1 the keys are "0.0-0.9",
+// "1.0-1.9", ..., "9.0-10.0" (a perfect 10.0 falls in the last bucket rather
+// than a bucket of its own). Scores are rounded to one decimal and clamped to
+// 0-10. Every bucket is present, with zero counts for empty ones, so charts
+// get a stable axis; use CVSSHistogramBuckets for the key order.
+// Returns nil if bucketSize is not positive.
+func CVSSHistogram(scores []float64, bucketSize float64) map[string]int {
+	width, count, ok := cvssBuckets(bucketSize)
+	if !ok {
+		return nil
+	}
+
+	counts := make([]int, count)
+	for _, score := range scores {
+		if math.IsNaN(score) {
+			continue
+		}
+		tenths := int(math.Round(math.Min(math.Max(score, 0), 10) * 10))
+		counts[min(tenths/width, count-1)]++
+	}
+
+	histogram := make(map[string]int, count)
+	for i, n := range counts {
+		histogram[cvssBucketLabel(i, width, count)] = n
+	}
+	return histogram
+}
+
+// CVSSHistogramBuckets returns the CVSSHistogram keys for bucketSize in
+// ascending order. Returns nil if bucketSize is not positive.
+func CVSSHistogramBuckets(bucketSize float64) []string {
+	width, count, ok := cvssBuckets(bucketSize)
+	if !ok {
+		return nil
+	}
+	labels := make([]string, count)
+	for i := range labels {
+		labels[i] = cvssBucketLabel(i, width, count)
+	}
+	return labels
+}
@@ -0,0 +1,84 @@
+package core
+
+import (
+	"strings"
+	"time"
+
+	"github.com/rediverio/sdk/pkg/ris"
+)
+
+// =============================================================================
+// Vulnerability Age & SLA
+// =============================================================================
+
+// FindingAge returns how long the finding's vulnerability has been publicly
+// known, measured from Vulnerability.PublishedAt (the advisory publish
+// date). Returns 0 if the publish date is unknown or after now.
+func FindingAge(f ris.Finding, now time.Time) time.Duration {
+	if f.Vulnerability == nil || f.Vulnerability.PublishedAt == nil {
+		return 0
+	}
+	return max(now.Sub(*f.Vulnerability.PublishedAt), 0)
+}
+
+// IsPastSLA reports whether the finding's age exceeds the SLA for its
+// severity, e.g. {"critical": 7 * 24 * time.Hour} flags criticals published
+// more than 7 days ago. Severity keys are case-insensitive. Findings with an
+// unknown publish date or a severity without an SLA entry are never past SLA.
+func IsPastSLA(f ris.Finding, sla map[string]time.Duration, now time.Time) bool {
+	if f.Vulnerability == nil || f.Vulnerability.PublishedAt == nil {
+		return false
+	}
+
+	limit, ok := slaFor(sla, string(f.Severity))
+	if !ok {
+		return false
+	}
+
+	return FindingAge(f, now) > limit
+}
+
+// RemediationDueDate returns when the finding must be remediated under an
+// SLA policy (see IsPastSLA): the vulnerability's PublishedAt, or the
+// finding's FirstSeenAt for findings without an advisory date, plus the SLA
+// for its severity. When the finding has no severity, it is derived from
+// Vulnerability.CVSSScore and CVSSVersion. Returns false if there is no
+// start date or no SLA entry for the severity.
+func RemediationDueDate(f ris.Finding, sla map[string]time.Duration) (time.Time, bool) {
+	var start *time.Time
+	if f.Vulnerability != nil && f.Vulnerability.PublishedAt != nil {
+		start = f.Vulnerability.PublishedAt
+	} else {
+		start = f.FirstSeenAt
+	}
+	if start == nil || start.IsZero() {
+		return time.Time{}, false
+	}
+
+	severity := string(f.Severity)
+	if severity == "" && f.Vulnerability != nil && f.Vulnerability.CVSSScore > 0 {
+		severity = SeverityFromCVSSVersion(f.Vulnerability.CVSSScore, CVSSVersion(f.Vulnerability.CVSSVersion))
+	}
+
+	limit, ok := slaFor(sla, severity)
+	if !ok {
+		return time.Time{}, false
+	}
+	return start.Add(limit), true
+}
+
+// slaFor looks up the SLA of a severity, matching keys case-insensitively.
+func slaFor(sla map[string]time.Duration, severity string) (time.Duration, bool) {
+	if severity == "" {
+		return 0, false
+	}
+	if limit, ok := sla[severity]; ok {
+		return limit, true
+	}
+	for sev, d := range sla {
+		if strings.EqualFold(sev, severity) {
+			return d, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,57 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// Code Snippets
+// =============================================================================
+
+// ExtractSnippet returns lines startLine to endLine (1-indexed, inclusive) of
+// fileContent with context lines before and after, for reports and review
+// output. Each line is prefixed with its line number, and lines of the
+// finding itself are marked with ">":
+//
+//	  11 | func handler(w http.ResponseWriter, r *http.Request) {
+//	> 12 | 	query := "SELECT * FROM users WHERE id = " + r.URL.Query().Get("id")
+//	  13 | 	rows, _ := db.Query(query)
+//
+// Out-of-range input is clamped rather than rejected: startLine below 1
+// becomes 1, endLine before startLine becomes startLine, endLine and the
+// context stop at the end of the file, and a negative context is 0. An empty
+// string is returned if startLine lies beyond the last line. CRLF line
+// endings are handled; the result has no trailing newline.
+func ExtractSnippet(fileContent []byte, startLine, endLine, context int) string {
+	content := strings.TrimSuffix(strings.ReplaceAll(string(fileContent), "\r\n", "\n"), "\n")
+	if content == "" {
+		return ""
+	}
+	lines := strings.Split(content, "\n")
+
+	startLine = max(startLine, 1)
+	endLine = min(max(endLine, startLine), len(lines))
+	if startLine > len(lines) {
+		return ""
+	}
+	context = max(context, 0)
+
+	first := max(startLine-context, 1)
+	last := min(endLine+context, len(lines))
+	width := len(strconv.Itoa(last))
+
+	var b strings.Builder
+	for n := first; n <= last; n++ {
+		marker := " "
+		if n >= startLine && n <= endLine {
+			marker = ">"
+		}
+		if n > first {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "%s %*d | %s", marker, width, n, lines[n-1])
+	}
+	return b.String()
+}
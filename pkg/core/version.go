@@ -0,0 +1,628 @@
+package core
+
+import (
+	"container/list"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rediverio/sdk/pkg/ris"
+)
+
+// =============================================================================
+// Version Parsing
+// =============================================================================
+
+// parsedVersion is a version split into its numeric release segments and an
+// optional qualifier (pre-release or post-release tag).
+type parsedVersion struct {
+	release   []int
+	qualifier string
+}
+
+// parseVersion parses a version string such as "v1.2.3-rc.1+build" into its
+// components. Build metadata (after "+") is ignored.
+func parseVersion(v string) (parsedVersion, error) {
+	s := strings.TrimSpace(v)
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "v"), "V")
+	if idx := strings.IndexByte(s, '+'); idx >= 0 {
+		s = s[:idx]
+	}
+
+	var pv parsedVersion
+	i := 0
+	for i < len(s) {
+		j := i
+		for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+			j++
+		}
+		if j == i {
+			break
+		}
+		n, err := strconv.Atoi(s[i:j])
+		if err != nil {
+			return parsedVersion{}, fmt.Errorf("invalid version %q: %w", v, err)
+		}
+		pv.release = append(pv.release, n)
+		i = j
+		// Continue only if a dot is followed by another digit
+		if i+1 < len(s) && s[i] == '.' && s[i+1] >= '0' && s[i+1] <= '9' {
+			i++
+			continue
+		}
+		break
+	}
+
+	if len(pv.release) == 0 {
+		return parsedVersion{}, fmt.Errorf("invalid version %q: no numeric component", v)
+	}
+
+	pv.qualifier = strings.ToLower(strings.TrimLeft(s[i:], "-._"))
+	return pv, nil
+}
+
+// =============================================================================
+// Parsed Version Cache
+// =============================================================================
+
+// DefaultVersionCacheSize is the default number of parsed versions cached.
+// SBOMs repeat the same handful of version strings across many packages,
+// so a few thousand entries cover most scans.
+const DefaultVersionCacheSize = 4096
+
+// versionCache is a bounded, thread-safe LRU cache of parsed versions.
+// Cached values share their release slice and must not be modified.
+type versionCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // Front is most recently used
+	entries map[string]*list.Element
+}
+
+type versionCacheEntry struct {
+	key string
+	pv  parsedVersion
+}
+
+var parsedVersions = newVersionCache(DefaultVersionCacheSize)
+
+func newVersionCache(size int) *versionCache {
+	return &versionCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (c *versionCache) get(key string) (parsedVersion, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return parsedVersion{}, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*versionCacheEntry).pv, true
+}
+
+func (c *versionCache) put(key string, pv parsedVersion) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.size <= 0 {
+		return
+	}
+	if el, ok := c.entries[key]; ok {
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&versionCacheEntry{key: key, pv: pv})
+	c.evict()
+}
+
+func (c *versionCache) resize(size int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.size = size
+	c.evict()
+}
+
+// evict drops least recently used entries beyond the size limit.
+// The caller must hold mu.
+func (c *versionCache) evict() {
+	for c.order.Len() > max(c.size, 0) {
+		el := c.order.Back()
+		c.order.Remove(el)
+		delete(c.entries, el.Value.(*versionCacheEntry).key)
+	}
+}
+
+// SetVersionCacheSize sets the maximum number of parsed versions cached for
+// CompareVersions and MinimalSafeVersion. Zero or negative disables the
+// cache and drops its entries. Results are identical with or without it.
+// The default is DefaultVersionCacheSize.
+func SetVersionCacheSize(n int) {
+	parsedVersions.resize(n)
+}
+
+// parseVersionCached is parseVersion backed by the parsed version cache.
+// Parse errors are not cached.
+func parseVersionCached(v string) (parsedVersion, error) {
+	if pv, ok := parsedVersions.get(v); ok {
+		return pv, nil
+	}
+	pv, err := parseVersion(v)
+	if err != nil {
+		return parsedVersion{}, err
+	}
+	parsedVersions.put(v, pv)
+	return pv, nil
+}
+
+// =============================================================================
+// Version Comparison
+// =============================================================================
+
+// CompareVersions compares two versions of a package in the given ecosystem.
+// It returns -1 if a < b, 0 if a == b, and +1 if a > b.
+//
+// Numeric release segments are compared numerically (missing segments count
+// as zero). A version with a pre-release qualifier sorts before the same
+// release without one ("1.2.0-beta.1" < "1.2.0"). Ecosystem-specific
+// qualifiers are honored: Maven "final"/"ga"/"release" equal the plain
+// release and "sp" sorts after it; PyPI "post" sorts after the release and
+// "dev" before alpha/beta/rc.
+//
+// An error is returned if either version has no numeric component.
+func CompareVersions(pkgType PackageType, a, b string) (int, error) {
+	va, err := parseVersionCached(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseVersionCached(b)
+	if err != nil {
+		return 0, err
+	}
+	return compareParsedVersions(pkgType, va, vb), nil
+}
+
+func compareParsedVersions(pkgType PackageType, a, b parsedVersion) int {
+	n := max(len(a.release), len(b.release))
+	for i := 0; i < n; i++ {
+		var x, y int
+		if i < len(a.release) {
+			x = a.release[i]
+		}
+		if i < len(b.release) {
+			y = b.release[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	ra, rb := qualifierRank(pkgType, a.qualifier), qualifierRank(pkgType, b.qualifier)
+	if ra != rb {
+		if ra < rb {
+			return -1
+		}
+		return 1
+	}
+	if ra != 0 {
+		return comparePrerelease(pkgType, a.qualifier, b.qualifier)
+	}
+	return 0
+}
+
+// qualifierRank classifies a qualifier: -1 pre-release, 0 release, +1 post-release.
+func qualifierRank(pkgType PackageType, q string) int {
+	switch {
+	case q == "":
+		return 0
+	case pkgType == PackageTypeMaven && (q == "final" || q == "ga" || q == "release"):
+		return 0
+	case pkgType == PackageTypeMaven && strings.HasPrefix(q, "sp"):
+		return 1
+	case pkgType == PackageTypePyPI && strings.HasPrefix(q, "post"):
+		return 1
+	default:
+		return -1
+	}
+}
+
+// comparePrerelease compares dot-separated qualifier identifiers following
+// semver precedence: numeric identifiers compare numerically and sort before
+// alphanumeric ones; a shorter identifier list sorts first when all preceding
+// identifiers are equal.
+func comparePrerelease(pkgType PackageType, a, b string) int {
+	if pkgType == PackageTypePyPI {
+		if ra, rb := pypiPreRank(a), pypiPreRank(b); ra != rb {
+			if ra < rb {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	pa := strings.FieldsFunc(a, isQualifierSeparator)
+	pb := strings.FieldsFunc(b, isQualifierSeparator)
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		if c := compareIdentifier(pa[i], pb[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(pa) < len(pb):
+		return -1
+	case len(pa) > len(pb):
+		return 1
+	default:
+		return 0
+	}
+}
+
+func isQualifierSeparator(r rune) bool {
+	return r == '.' || r == '-' || r == '_'
+}
+
+func compareIdentifier(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		switch {
+		case na < nb:
+			return -1
+		case na > nb:
+			return 1
+		}
+		return 0
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// pypiPreRank orders PEP 440 pre-release phases: dev < a < b < rc.
+func pypiPreRank(q string) int {
+	switch {
+	case strings.HasPrefix(q, "dev"):
+		return 0
+	case strings.HasPrefix(q, "alpha"), strings.HasPrefix(q, "a"):
+		return 1
+	case strings.HasPrefix(q, "beta"), strings.HasPrefix(q, "b"):
+		return 2
+	case strings.HasPrefix(q, "rc"), strings.HasPrefix(q, "c"), strings.HasPrefix(q, "pre"):
+		return 3
+	default:
+		return 4
+	}
+}
+
+// =============================================================================
+// Semantic Versioning
+// =============================================================================
+
+// semverPattern matches a SemVer 2.0.0 version, with an optional "v" prefix.
+var semverPattern = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+[0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*)?$`)
+
+// CompareSemver compares two semantic versions following SemVer 2.0.0
+// precedence. It returns -1, 0 or +1 as a is lower than, equal to or higher
+// than b. A pre-release sorts before its release ("1.2.0-beta.1" < "1.2.0"),
+// pre-release identifiers compare numerically or lexically per the spec, and
+// build metadata is ignored. A leading "v" is accepted.
+//
+// Unlike CompareVersions, it does not guess: input that is not a full
+// MAJOR.MINOR.PATCH semantic version (e.g. "1.2" or "1.2.0.Final") is an
+// error.
+func CompareSemver(a, b string) (int, error) {
+	ma := semverPattern.FindStringSubmatch(strings.TrimSpace(a))
+	if ma == nil {
+		return 0, fmt.Errorf("invalid semantic version %q", a)
+	}
+	mb := semverPattern.FindStringSubmatch(strings.TrimSpace(b))
+	if mb == nil {
+		return 0, fmt.Errorf("invalid semantic version %q", b)
+	}
+
+	for i := 1; i <= 3; i++ {
+		if c := compareNumericIdentifier(ma[i], mb[i]); c != 0 {
+			return c, nil
+		}
+	}
+
+	preA, preB := ma[4], mb[4]
+	switch {
+	case preA == preB:
+		return 0, nil
+	case preA == "":
+		return 1, nil
+	case preB == "":
+		return -1, nil
+	}
+
+	ia, ib := strings.Split(preA, "."), strings.Split(preB, ".")
+	for i := 0; i < len(ia) && i < len(ib); i++ {
+		na, nb := isNumericIdentifier(ia[i]), isNumericIdentifier(ib[i])
+		var c int
+		switch {
+		case na && nb:
+			c = compareNumericIdentifier(ia[i], ib[i])
+		case na:
+			c = -1
+		case nb:
+			c = 1
+		default:
+			c = strings.Compare(ia[i], ib[i])
+		}
+		if c != 0 {
+			return c, nil
+		}
+	}
+	switch {
+	case len(ia) < len(ib):
+		return -1, nil
+	case len(ia) > len(ib):
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// isNumericIdentifier reports whether a semver identifier is all digits.
+func isNumericIdentifier(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// compareNumericIdentifier compares digit strings without leading zeros by
+// value, without overflowing on arbitrarily long numbers.
+func compareNumericIdentifier(a, b string) int {
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// =============================================================================
+// Remediation Helpers
+// =============================================================================
+
+// MinimalSafeVersion returns the smallest version >= current that contains
+// every fix in fixedVersions.
+//
+// Each entry of fixedVersions is the fixed version of one vulnerability. An
+// entry may list alternatives for different release lines separated by
+// commas (as Trivy reports them, e.g. "2.4.1, 3.0.2"). A version contains an
+// entry's fix if it is at or above the entry's highest alternative, or if
+// an alternative at or below it is on the same release line (the same
+// version segments except the last, so "2.4.3" is on the line of "2.4.1"
+// but "3.0.1" is not on the line of "3.0.2" when below it). Candidates are
+// current and every alternative above it, and the smallest candidate that
+// contains all fixes is returned, so release lines are never mixed. When
+// nothing needs upgrading, current itself is returned.
+//
+// An error is returned if fixedVersions is empty or any version is not
+// comparable.
+func MinimalSafeVersion(pkgType PackageType, current string, fixedVersions []string) (string, error) {
+	if len(fixedVersions) == 0 {
+		return "", fmt.Errorf("no fixed versions provided")
+	}
+
+	cur, err := parseVersionCached(current)
+	if err != nil {
+		return "", err
+	}
+
+	type candidate struct {
+		raw    string
+		parsed parsedVersion
+	}
+	candidates := []candidate{{current, cur}}
+	entries := make([][]parsedVersion, 0, len(fixedVersions))
+
+	for _, entry := range fixedVersions {
+		var alts []parsedVersion
+		for _, alt := range strings.Split(entry, ",") {
+			alt = strings.TrimSpace(alt)
+			if alt == "" {
+				continue
+			}
+			pv, err := parseVersionCached(alt)
+			if err != nil {
+				return "", err
+			}
+			alts = append(alts, pv)
+			if compareParsedVersions(pkgType, pv, cur) > 0 {
+				candidates = append(candidates, candidate{alt, pv})
+			}
+		}
+		if len(alts) > 0 {
+			entries = append(entries, alts)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return compareParsedVersions(pkgType, candidates[i].parsed, candidates[j].parsed) < 0
+	})
+	for _, c := range candidates {
+		fixed := true
+		for _, alts := range entries {
+			if !containsFix(pkgType, c.parsed, alts) {
+				fixed = false
+				break
+			}
+		}
+		if fixed {
+			return c.raw, nil
+		}
+	}
+
+	// Unreachable: the highest candidate is at or above every entry's
+	// highest alternative
+	return candidates[len(candidates)-1].raw, nil
+}
+
+// containsFix reports whether version v contains the fix of a
+// vulnerability fixed in any of alts (see MinimalSafeVersion).
+func containsFix(pkgType PackageType, v parsedVersion, alts []parsedVersion) bool {
+	highest := alts[0]
+	for _, alt := range alts {
+		if compareParsedVersions(pkgType, alt, highest) > 0 {
+			highest = alt
+		}
+		if compareParsedVersions(pkgType, alt, v) <= 0 && sameReleaseLine(alt, v) {
+			return true
+		}
+	}
+	return compareParsedVersions(pkgType, v, highest) >= 0
+}
+
+// sameReleaseLine reports whether v is on the release line of alt: equal in
+// every release segment of alt but the last (the first, for single-segment
+// versions). Missing segments count as 0.
+func sameReleaseLine(alt, v parsedVersion) bool {
+	n := max(len(alt.release)-1, 1)
+	for i := 0; i < n; i++ {
+		var x, y int
+		if i < len(alt.release) {
+			x = alt.release[i]
+		}
+		if i < len(v.release) {
+			y = v.release[i]
+		}
+		if x != y {
+			return false
+		}
+	}
+	return true
+}
+
+// UpgradeStep is one package upgrade of an UpgradePlan.
+type UpgradeStep struct {
+	Package        string `yaml:"package" json:"package"`
+	Ecosystem      string `yaml:"ecosystem,omitempty" json:"ecosystem,omitempty"`
+	CurrentVersion string `yaml:"current_version" json:"current_version"`
+	TargetVersion  string `yaml:"target_version" json:"target_version"`
+
+	// Vulnerabilities are the advisory IDs resolved by the upgrade, sorted.
+	Vulnerabilities []string `yaml:"vulnerabilities" json:"vulnerabilities"`
+	// FixedCount is len(Vulnerabilities).
+	FixedCount int `yaml:"fixed_count" json:"fixed_count"`
+}
+
+// UpgradePlan turns SCA findings into an upgrade checklist: one step per
+// installed package version, with the minimal version that fixes all of its
+// vulnerabilities (see MinimalSafeVersion) and the advisories it resolves.
+//
+// Only findings with a package, an affected version and a fixed version
+// (FixedVersion, else the alternatives in FixedVersions) are considered.
+// Advisory IDs are normalized, so a vulnerability reported twice counts
+// once. Packages whose versions cannot be compared, or whose fixes are all
+// at or below the current version, are left out. Steps are sorted by
+// package name, then current version.
+func UpgradePlan(findings []ris.Finding) []UpgradeStep {
+	type group struct {
+		step  UpgradeStep
+		fixes []string
+		vulns map[string]bool
+	}
+	groups := make(map[string]*group)
+
+	for _, f := range findings {
+		v := f.Vulnerability
+		if v == nil || v.Package == "" || v.AffectedVersion == "" {
+			continue
+		}
+		fix := v.FixedVersion
+		if fix == "" {
+			fix = strings.Join(v.FixedVersions, ", ")
+		}
+		if strings.TrimSpace(fix) == "" {
+			continue
+		}
+
+		key := v.Ecosystem + "|" + v.Package + "@" + v.AffectedVersion
+		g, ok := groups[key]
+		if !ok {
+			g = &group{
+				step: UpgradeStep{
+					Package:        v.Package,
+					Ecosystem:      v.Ecosystem,
+					CurrentVersion: v.AffectedVersion,
+				},
+				vulns: make(map[string]bool),
+			}
+			groups[key] = g
+		}
+		g.fixes = append(g.fixes, fix)
+		if v.CVEID != "" {
+			g.vulns[normalizeAdvisory(v.CVEID)] = true
+		}
+	}
+
+	steps := make([]UpgradeStep, 0, len(groups))
+	for _, g := range groups {
+		target, err := MinimalSafeVersion(ecosystemPackageType(g.step.Ecosystem), g.step.CurrentVersion, g.fixes)
+		if err != nil || target == g.step.CurrentVersion {
+			continue
+		}
+		g.step.TargetVersion = target
+		for id := range g.vulns {
+			g.step.Vulnerabilities = append(g.step.Vulnerabilities, id)
+		}
+		sort.Strings(g.step.Vulnerabilities)
+		g.step.FixedCount = len(g.step.Vulnerabilities)
+		steps = append(steps, g.step)
+	}
+
+	sort.Slice(steps, func(i, j int) bool {
+		if steps[i].Package != steps[j].Package {
+			return steps[i].Package < steps[j].Package
+		}
+		return steps[i].CurrentVersion < steps[j].CurrentVersion
+	})
+	return steps
+}
+
+// ecosystemPackageType maps a finding's ecosystem name to a PackageType for
+// version comparison. Unknown ecosystems map to "", which compares versions
+// with the generic rules.
+func ecosystemPackageType(ecosystem string) PackageType {
+	switch strings.ToLower(ecosystem) {
+	case "npm", "yarn", "pnpm":
+		return PackageTypeNPM
+	case "pip", "pypi", "python", "poetry", "pipenv":
+		return PackageTypePyPI
+	case "maven", "gradle", "java":
+		return PackageTypeMaven
+	case "go", "golang", "gomod":
+		return PackageTypeGo
+	case "cargo", "crates.io", "rust":
+		return PackageTypeCargo
+	case "nuget", ".net":
+		return PackageTypeNuGet
+	case "gem", "rubygems", "bundler":
+		return PackageTypeGem
+	case "composer", "packagist":
+		return PackageTypeComposer
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,167 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/rediverio/sdk/pkg/ris"
+)
+
+// makeSBOMVersions simulates the versions of an SBOM's components: many
+// packages, few distinct version strings.
+func makeSBOMVersions(n int) []string {
+	versions := make([]string, n)
+	for i := range versions {
+		switch i % 4 {
+		case 0:
+			versions[i] = fmt.Sprintf("1.%d.%d", i%12, i%5)
+		case 1:
+			versions[i] = fmt.Sprintf("v2.%d.0-rc.%d", i%8, i%3)
+		case 2:
+			versions[i] = fmt.Sprintf("4.%d.%d.Final", i%6, i%4)
+		default:
+			versions[i] = fmt.Sprintf("0.%d.%dpost%d", i%10, i%7, i%2)
+		}
+	}
+	return versions
+}
+
+func compareAll(pkgType PackageType, versions []string, fixed string) ([]int, error) {
+	results := make([]int, len(versions))
+	for i, v := range versions {
+		c, err := CompareVersions(pkgType, v, fixed)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = c
+	}
+	return results, nil
+}
+
+func TestCompareVersions_CacheEquivalence(t *testing.T) {
+	defer SetVersionCacheSize(DefaultVersionCacheSize)
+
+	versions := makeSBOMVersions(5000)
+	for _, pkgType := range []PackageType{PackageTypeNPM, PackageTypeMaven, PackageTypePyPI} {
+		SetVersionCacheSize(0)
+		want, err := compareAll(pkgType, versions, "1.5.0")
+		if err != nil {
+			t.Fatalf("%s uncached: %v", pkgType, err)
+		}
+
+		// A small cache forces evictions as well as hits.
+		for _, size := range []int{16, DefaultVersionCacheSize} {
+			SetVersionCacheSize(size)
+			for pass := 0; pass < 2; pass++ {
+				got, err := compareAll(pkgType, versions, "1.5.0")
+				if err != nil {
+					t.Fatalf("%s size=%d: %v", pkgType, size, err)
+				}
+				for i := range want {
+					if got[i] != want[i] {
+						t.Fatalf("%s size=%d pass=%d: CompareVersions(%q) = %d, uncached %d",
+							pkgType, size, pass, versions[i], got[i], want[i])
+					}
+				}
+			}
+			if n := parsedVersions.order.Len(); n > size {
+				t.Errorf("size=%d: cache holds %d entries", size, n)
+			}
+		}
+	}
+}
+
+func TestCompareVersions_CacheErrors(t *testing.T) {
+	defer SetVersionCacheSize(DefaultVersionCacheSize)
+	SetVersionCacheSize(DefaultVersionCacheSize)
+
+	for pass := 0; pass < 2; pass++ {
+		if _, err := CompareVersions(PackageTypeNPM, "latest", "1.0.0"); err == nil {
+			t.Fatalf("pass %d: expected error for non-numeric version", pass)
+		}
+	}
+}
+
+func BenchmarkCompareVersions_SBOM(b *testing.B) {
+	defer SetVersionCacheSize(DefaultVersionCacheSize)
+
+	versions := makeSBOMVersions(10000)
+	for _, size := range []int{0, DefaultVersionCacheSize} {
+		b.Run(fmt.Sprintf("cache=%d", size), func(b *testing.B) {
+			SetVersionCacheSize(size)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for _, v := range versions {
+					_, _ = CompareVersions(PackageTypeMaven, v, "1.5.0")
+				}
+			}
+		})
+	}
+}
+
+func TestCompareSemver_Precedence(t *testing.T) {
+	// Ascending order from the SemVer 2.0.0 spec, plus prefix and build cases
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "v1.0.1+build.5", "1.10.0",
+	}
+	for i := 0; i+1 < len(ordered); i++ {
+		if c, err := CompareSemver(ordered[i], ordered[i+1]); c != -1 || err != nil {
+			t.Errorf("CompareSemver(%q, %q) = %d, %v; want -1", ordered[i], ordered[i+1], c, err)
+		}
+	}
+	if c, err := CompareSemver("1.2.3+a", "v1.2.3+b"); c != 0 || err != nil {
+		t.Errorf("build metadata should be ignored, got %d, %v", c, err)
+	}
+
+	for _, bad := range []string{"1.2", "01.2.3", "1.2.3-", "1.2.3-01", "1.2.3.Final", "latest"} {
+		if _, err := CompareSemver(bad, "1.0.0"); err == nil {
+			t.Errorf("CompareSemver(%q) should fail", bad)
+		}
+	}
+}
+
+func TestMinimalSafeVersion_DoesNotMixReleaseLines(t *testing.T) {
+	tests := []struct {
+		current string
+		fixed   []string
+		want    string
+	}{
+		// 3.0.1 fixes the second advisory but not the first on the 3.x line
+		{"2.3.0", []string{"2.4.1, 3.0.2", "3.0.1"}, "3.0.2"},
+		{"2.3.0", []string{"2.4.1, 3.0.2", "2.4.0"}, "2.4.1"},
+		{"2.4.1", []string{"2.4.1, 3.0.2"}, "2.4.1"},
+		{"1.0.0", []string{"1.2.0", "1.1.0"}, "1.2.0"},
+		// 2.13.0 is not on the 2.12 backport line and below the 2.16.0 fix
+		{"2.12.0", []string{"2.12.2, 2.16.0", "2.13.0"}, "2.16.0"},
+	}
+	for _, tt := range tests {
+		got, err := MinimalSafeVersion(PackageTypeNPM, tt.current, tt.fixed)
+		if err != nil || got != tt.want {
+			t.Errorf("MinimalSafeVersion(%q, %q) = %q, %v; want %q", tt.current, tt.fixed, got, err, tt.want)
+		}
+	}
+}
+
+func TestUpgradePlan_MultiLineFixes(t *testing.T) {
+	vuln := func(id, fixed string) ris.Finding {
+		return ris.Finding{Vulnerability: &ris.VulnerabilityDetails{
+			Package: "lib", Ecosystem: "npm", AffectedVersion: "2.3.0", FixedVersion: fixed, CVEID: id,
+		}}
+	}
+	plan := UpgradePlan([]ris.Finding{
+		vuln("CVE-2024-0001", "2.4.1, 3.0.2"),
+		vuln("CVE-2024-0002", "3.0.1"),
+	})
+
+	if len(plan) != 1 {
+		t.Fatalf("UpgradePlan returned %d steps, want 1", len(plan))
+	}
+	step := plan[0]
+	if step.TargetVersion != "3.0.2" {
+		t.Errorf("TargetVersion = %q, want %q", step.TargetVersion, "3.0.2")
+	}
+	if step.FixedCount != 2 {
+		t.Errorf("FixedCount = %d, want 2", step.FixedCount)
+	}
+}
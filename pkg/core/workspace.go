@@ -0,0 +1,126 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// =============================================================================
+// Workspace - Scratch directory management for scanners
+// =============================================================================
+
+// Workspace is a temporary scratch directory for a scanner run.
+// Always call Close when done to remove the directory tree.
+//
+// Example:
+//
+//	ws, err := core.NewWorkspace("trivy")
+//	if err != nil {
+//	    return err
+//	}
+//	defer ws.Close()
+//
+//	cfg := &core.ExecConfig{Binary: "trivy", WorkDir: ws.Dir()}
+type Workspace struct {
+	dir    string
+	closed bool
+	mu     sync.Mutex
+}
+
+// NewWorkspace creates a new temporary workspace directory.
+// The prefix is used in the directory name to identify its owner.
+func NewWorkspace(prefix string) (*Workspace, error) {
+	if prefix == "" {
+		prefix = "rediver"
+	}
+	dir, err := os.MkdirTemp("", prefix+"-")
+	if err != nil {
+		return nil, fmt.Errorf("create workspace: %w", err)
+	}
+	return &Workspace{dir: dir}, nil
+}
+
+// Dir returns the workspace root directory.
+func (w *Workspace) Dir() string {
+	return w.dir
+}
+
+// Subdir creates (if needed) and returns a subdirectory of the workspace.
+// The name must be relative and must not escape the workspace root.
+func (w *Workspace) Subdir(name string) (string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return "", fmt.Errorf("workspace is closed")
+	}
+
+	cleaned := filepath.Clean(name)
+	if filepath.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid subdirectory name: %s", name)
+	}
+
+	path := filepath.Join(w.dir, cleaned)
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return "", fmt.Errorf("create subdirectory: %w", err)
+	}
+	return path, nil
+}
+
+// Close removes the workspace directory tree.
+// It is idempotent and tolerates the directory having already been removed.
+func (w *Workspace) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	if err := os.RemoveAll(w.dir); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove workspace: %w", err)
+	}
+	return nil
+}
+
+// WriteConfigToTemp materializes in-memory content (e.g. a generated policy or
+// ruleset) to a file in a fresh temporary directory, so its path can be passed
+// to a scanner. The name is used as the file's base name, which keeps the
+// extension scanners often rely on (e.g. "rules.yaml").
+//
+// The returned cleanup function removes the file and its directory; it is
+// safe to call more than once. Cleanup is a no-op when an error is returned.
+//
+// Example:
+//
+//	path, cleanup, err := core.WriteConfigToTemp(rules, "rules.yaml")
+//	if err != nil {
+//	    return err
+//	}
+//	defer cleanup()
+//	cfg.Args = append(cfg.Args, "--config", path)
+func WriteConfigToTemp(content []byte, name string) (string, func(), error) {
+	noop := func() {}
+
+	base := filepath.Base(filepath.Clean(name))
+	if base == "." || base == ".." || base == string(filepath.Separator) {
+		return "", noop, fmt.Errorf("invalid config file name: %q", name)
+	}
+
+	ws, err := NewWorkspace("rediver-config")
+	if err != nil {
+		return "", noop, err
+	}
+
+	path := filepath.Join(ws.Dir(), base)
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		_ = ws.Close()
+		return "", noop, fmt.Errorf("write config file: %w", err)
+	}
+
+	return path, func() { _ = ws.Close() }, nil
+}
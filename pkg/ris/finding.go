@@ -0,0 +1,258 @@
+package ris
+
+import (
+	"sort"
+	"strings"
+)
+
+// =============================================================================
+// Finding Helpers
+// =============================================================================
+
+// HasFix reports whether a fix is available for the finding.
+//
+// For SCA findings this is true when Vulnerability.FixedVersion is set.
+// SAST, secret and other findings have no fixed version, so HasFix is false
+// unless the producer explicitly set Remediation.FixAvailable.
+func (f *Finding) HasFix() bool {
+	if f.Vulnerability != nil && f.Vulnerability.FixedVersion != "" {
+		return true
+	}
+	return f.Remediation != nil && f.Remediation.FixAvailable
+}
+
+// =============================================================================
+// Finding Provenance
+// =============================================================================
+
+// ApplyProvenance sets the Provenance of every finding that has none from
+// the report's tool and metadata: Tool.Name and Tool.Version, Metadata.ID
+// as the scan ID, and Metadata.Timestamp as the scan time. Parsers call this
+// after building a report; findings with existing provenance are untouched.
+func (r *Report) ApplyProvenance() {
+	prov := Provenance{
+		ScanID:    r.Metadata.ID,
+		ScannedAt: r.Metadata.Timestamp,
+	}
+	if r.Tool != nil {
+		prov.Scanner = r.Tool.Name
+		prov.ScannerVersion = r.Tool.Version
+	}
+
+	for i := range r.Findings {
+		if r.Findings[i].Provenance == nil {
+			p := prov
+			r.Findings[i].Provenance = &p
+		}
+	}
+}
+
+// FilterByScanner returns the findings produced by scanner, compared
+// case-insensitively against Provenance.Scanner. Findings without
+// provenance never match.
+func FilterByScanner(findings []Finding, scanner string) []Finding {
+	result := make([]Finding, 0)
+	for _, f := range findings {
+		if f.Provenance != nil && strings.EqualFold(f.Provenance.Scanner, scanner) {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// =============================================================================
+// Finding Tags
+// =============================================================================
+
+// tagSeparator separates the key and value of a key:value tag.
+const tagSeparator = ":"
+
+// AddTag sets a key:value label (e.g. "team:payments") in Tags, replacing
+// any existing value for key. An empty value adds the bare tag key.
+// Labels share Tags with plain scanner tags such as "sca"; keys must not
+// contain ":".
+func (f *Finding) AddTag(key, value string) {
+	tag := key
+	if value != "" {
+		tag = key + tagSeparator + value
+	}
+	for i, t := range f.Tags {
+		if tagKey(t) == key {
+			f.Tags[i] = tag
+			return
+		}
+	}
+	f.Tags = append(f.Tags, tag)
+}
+
+// HasTag reports whether the finding has the label key with the given value.
+// An empty value matches any value of key, including the bare tag key.
+func (f *Finding) HasTag(key, value string) bool {
+	for _, t := range f.Tags {
+		k, v, _ := strings.Cut(t, tagSeparator)
+		if k == key && (value == "" || v == value) {
+			return true
+		}
+	}
+	return false
+}
+
+// TagValue returns the value of the label key, and whether it is present.
+func (f *Finding) TagValue(key string) (string, bool) {
+	for _, t := range f.Tags {
+		if k, v, _ := strings.Cut(t, tagSeparator); k == key {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// FilterByTag returns the findings that have the label key with the given
+// value (see HasTag), preserving order.
+func FilterByTag(findings []Finding, key, value string) []Finding {
+	result := make([]Finding, 0)
+	for i := range findings {
+		if findings[i].HasTag(key, value) {
+			result = append(result, findings[i])
+		}
+	}
+	return result
+}
+
+// tagKey returns the key of a key:value tag, or the whole tag if bare.
+func tagKey(tag string) string {
+	k, _, _ := strings.Cut(tag, tagSeparator)
+	return k
+}
+
+// =============================================================================
+// Finding Grouping
+// =============================================================================
+
+// GroupFindings groups findings by the key returned by keyFn.
+// Within each group, findings keep their input order.
+func GroupFindings(findings []Finding, keyFn func(Finding) string) map[string][]Finding {
+	groups := make(map[string][]Finding)
+	for _, f := range findings {
+		key := keyFn(f)
+		groups[key] = append(groups[key], f)
+	}
+	return groups
+}
+
+// GroupByFile groups findings by location path.
+// Findings without a location are grouped under "".
+func GroupByFile(findings []Finding) map[string][]Finding {
+	return GroupFindings(findings, func(f Finding) string {
+		if f.Location == nil {
+			return ""
+		}
+		return f.Location.Path
+	})
+}
+
+// GroupByRule groups findings by rule ID.
+func GroupByRule(findings []Finding) map[string][]Finding {
+	return GroupFindings(findings, func(f Finding) string {
+		return f.RuleID
+	})
+}
+
+// GroupByPackage groups findings by affected package name.
+// Findings without vulnerability details are grouped under "".
+func GroupByPackage(findings []Finding) map[string][]Finding {
+	return GroupFindings(findings, func(f Finding) string {
+		if f.Vulnerability == nil {
+			return ""
+		}
+		return f.Vulnerability.Package
+	})
+}
+
+// GroupBySeverity groups findings by severity.
+func GroupBySeverity(findings []Finding) map[string][]Finding {
+	return GroupFindings(findings, func(f Finding) string {
+		return string(f.Severity)
+	})
+}
+
+// GroupSecretsByValue groups secret findings by the hash of their secret value
+// (Secret.ValueHash), so every occurrence of the same leaked secret across
+// files can be covered by one rotation alert. Findings without a value hash
+// are omitted.
+func GroupSecretsByValue(findings []Finding) map[string][]Finding {
+	groups := make(map[string][]Finding)
+	for _, f := range findings {
+		if f.Secret == nil || f.Secret.ValueHash == "" {
+			continue
+		}
+		groups[f.Secret.ValueHash] = append(groups[f.Secret.ValueHash], f)
+	}
+	return groups
+}
+
+// =============================================================================
+// Finding Ordering
+// =============================================================================
+
+// CanonicalOrder sorts findings in place by file path, start line, rule ID
+// and fingerprint, so serialized reports have a deterministic order: diffs
+// of two reports show real changes and content hashes are stable. Findings
+// without a location sort first. The sort is stable, so findings equal on
+// all four keys keep their relative order. Exporters such as ToSARIF apply
+// it to a copy before serialization.
+func CanonicalOrder(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := &findings[i], &findings[j]
+		if pa, pb := findingPath(a), findingPath(b); pa != pb {
+			return pa < pb
+		}
+		if la, lb := findingLine(a), findingLine(b); la != lb {
+			return la < lb
+		}
+		if a.RuleID != b.RuleID {
+			return a.RuleID < b.RuleID
+		}
+		return a.Fingerprint < b.Fingerprint
+	})
+}
+
+// findingPath returns the location path of a finding, or "".
+func findingPath(f *Finding) string {
+	if f.Location == nil {
+		return ""
+	}
+	return f.Location.Path
+}
+
+// findingLine returns the start line of a finding, or 0.
+func findingLine(f *Finding) int {
+	if f.Location == nil {
+		return 0
+	}
+	return f.Location.StartLine
+}
+
+// =============================================================================
+// Dependency Depth
+// =============================================================================
+
+// IsDirectDependency reports whether a finding affects a direct dependency,
+// per Vulnerability.IsDirect or a DependencyDepth of 1.
+func (f *Finding) IsDirectDependency() bool {
+	v := f.Vulnerability
+	return v != nil && (v.IsDirect || v.DependencyDepth == 1)
+}
+
+// FilterDirectOnly returns the findings that affect direct dependencies (see
+// IsDirectDependency), for "fix your direct dependencies first" views.
+// Findings without dependency information are excluded.
+func FilterDirectOnly(findings []Finding) []Finding {
+	var result []Finding
+	for _, f := range findings {
+		if f.IsDirectDependency() {
+			result = append(result, f)
+		}
+	}
+	return result
+}
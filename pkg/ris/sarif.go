@@ -5,7 +5,10 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf16"
 )
 
 // =============================================================================
@@ -60,13 +63,24 @@ type SARIFRuleConfig struct {
 
 // SARIFResult represents a finding.
 type SARIFResult struct {
-	RuleID       string            `json:"ruleId"`
-	RuleIndex    int               `json:"ruleIndex,omitempty"`
-	Level        string            `json:"level,omitempty"`
-	Message      SARIFMessage      `json:"message"`
-	Locations    []SARIFLocation   `json:"locations,omitempty"`
-	Fingerprints map[string]string `json:"fingerprints,omitempty"`
-	Properties   map[string]any    `json:"properties,omitempty"`
+	RuleID              string             `json:"ruleId"`
+	RuleIndex           int                `json:"ruleIndex,omitempty"`
+	Level               string             `json:"level,omitempty"`
+	Message             SARIFMessage       `json:"message"`
+	Locations           []SARIFLocation    `json:"locations,omitempty"`
+	Fingerprints        map[string]string  `json:"fingerprints,omitempty"`
+	PartialFingerprints map[string]string  `json:"partialFingerprints,omitempty"`
+	Suppressions        []SARIFSuppression `json:"suppressions,omitempty"`
+	Properties          map[string]any     `json:"properties,omitempty"`
+}
+
+// SARIFSuppression marks a result as suppressed (e.g. an accepted risk).
+// GitHub code scanning shows suppressed results as dismissed.
+type SARIFSuppression struct {
+	Kind          string         `json:"kind"`             // inSource, external
+	Status        string         `json:"status,omitempty"` // accepted, underReview, rejected
+	Justification string         `json:"justification,omitempty"`
+	Properties    map[string]any `json:"properties,omitempty"`
 }
 
 // SARIFMessage holds text.
@@ -286,6 +300,8 @@ func FromSARIF(data []byte, opts *ConvertOptions) (*Report, error) {
 		report.Findings = append(report.Findings, finding)
 	}
 
+	report.ApplyProvenance()
+
 	return report, nil
 }
 
@@ -375,3 +391,311 @@ func detectCapabilities(toolName string, toolType string) []string {
 
 	return []string{"vulnerability", "secret"}
 }
+
+// =============================================================================
+// RIS to SARIF Conversion
+// =============================================================================
+
+// SARIFVersion is the SARIF specification version emitted by ToSARIF.
+const SARIFVersion = "2.1.0"
+
+// SARIFSchema is the JSON schema URL for SARIF 2.1.0.
+const SARIFSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// sarifFingerprintKey is the partialFingerprints/fingerprints key used for
+// RIS fingerprints.
+const sarifFingerprintKey = "rediver/v1"
+
+// ToSARIF converts a RIS report to a SARIF log with a single run.
+// Finding locations are emitted as full regions (start/end line and column)
+// so code-review UIs can highlight the exact range. Results are emitted in
+// CanonicalOrder; the report itself is not reordered. Suppressed findings,
+// such as accepted risks, are emitted with a SARIF suppression, so export
+// them rather than dropping them to keep the audit trail.
+func ToSARIF(report *Report) (*SARIFLog, error) {
+	if report == nil {
+		return nil, fmt.Errorf("report is nil")
+	}
+
+	findings := append([]Finding(nil), report.Findings...)
+	CanonicalOrder(findings)
+
+	run := SARIFRun{
+		Results: make([]SARIFResult, 0, len(findings)),
+	}
+	if report.Tool != nil {
+		run.Tool.Driver = SARIFDriver{
+			Name:           report.Tool.Name,
+			Version:        report.Tool.Version,
+			InformationURI: report.Tool.InfoURL,
+		}
+	}
+	if run.Tool.Driver.Name == "" {
+		run.Tool.Driver.Name = "rediver"
+	}
+
+	ruleIndex := make(map[string]int)
+	for i := range findings {
+		f := &findings[i]
+
+		ruleID := f.RuleID
+		if ruleID == "" {
+			ruleID = string(f.Type)
+		}
+		idx, ok := ruleIndex[ruleID]
+		if !ok {
+			idx = len(run.Tool.Driver.Rules)
+			ruleIndex[ruleID] = idx
+			rule := SARIFRule{
+				ID:                   ruleID,
+				Name:                 f.RuleName,
+				DefaultConfiguration: &SARIFRuleConfig{Level: severityToSARIFLevel(f.Severity)},
+			}
+			if f.Description != "" {
+				rule.ShortDescription = &SARIFMessage{Text: f.Description}
+			}
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+		}
+
+		message := f.Title
+		if message == "" {
+			message = f.Description
+		}
+
+		result := SARIFResult{
+			RuleID:    ruleID,
+			RuleIndex: idx,
+			Level:     severityToSARIFLevel(f.Severity),
+			Message:   SARIFMessage{Text: message},
+		}
+		if loc := toSARIFLocation(f.Location); loc != nil {
+			result.Locations = []SARIFLocation{*loc}
+		}
+		if f.Fingerprint != "" {
+			result.Fingerprints = map[string]string{sarifFingerprintKey: f.Fingerprint}
+		}
+		result.PartialFingerprints = GeneratePartialFingerprints(*f)
+		if sup := toSARIFSuppression(f); sup != nil {
+			result.Suppressions = []SARIFSuppression{*sup}
+		}
+
+		run.Results = append(run.Results, result)
+	}
+
+	return &SARIFLog{
+		Version: SARIFVersion,
+		Schema:  SARIFSchema,
+		Runs:    []SARIFRun{run},
+	}, nil
+}
+
+// toSARIFSuppression converts the suppression of a finding, such as an
+// accepted risk (see core.ApplyAcceptedRisks), to a SARIF suppression, so the
+// finding is exported as suppressed instead of being omitted. The approver
+// and expiry are kept as suppression properties. Returns nil for findings
+// that are not suppressed.
+func toSARIFSuppression(f *Finding) *SARIFSuppression {
+	sup := f.Suppression
+	if sup == nil {
+		if f.Status != FindingStatusAcceptedRisk {
+			return nil
+		}
+		sup = &Suppression{Status: "accepted"}
+	}
+
+	result := &SARIFSuppression{
+		Kind:          "external",
+		Justification: sup.Justification,
+	}
+	if sup.Kind == "in_source" {
+		result.Kind = "inSource"
+	}
+	switch sup.Status {
+	case "accepted", "rejected":
+		result.Status = sup.Status
+	case "under_review":
+		result.Status = "underReview"
+	}
+
+	props := make(map[string]any)
+	if sup.SuppressedBy != "" {
+		props["suppressedBy"] = sup.SuppressedBy
+	}
+	if sup.SuppressedAt != nil {
+		props["suppressedAt"] = sup.SuppressedAt.UTC().Format(time.RFC3339)
+	}
+	if sup.ExpiresAt != nil {
+		props["expiresAt"] = sup.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	if len(props) > 0 {
+		result.Properties = props
+	}
+	return result
+}
+
+// toSARIFLocation converts a finding location to a SARIF location.
+// Returns nil if the location has no path.
+func toSARIFLocation(loc *FindingLocation) *SARIFLocation {
+	if loc == nil || loc.Path == "" {
+		return nil
+	}
+
+	physical := &SARIFPhysicalLocation{
+		ArtifactLocation: &SARIFArtifactLocation{URI: loc.Path},
+	}
+
+	if loc.StartLine > 0 {
+		region := &SARIFRegion{
+			StartLine:   loc.StartLine,
+			StartColumn: loc.StartColumn,
+			EndColumn:   loc.EndColumn,
+		}
+		if loc.EndLine >= loc.StartLine {
+			region.EndLine = loc.EndLine
+		}
+		if loc.Snippet != "" {
+			region.Snippet = &SARIFSnippet{Text: loc.Snippet}
+		}
+		physical.Region = region
+	}
+
+	return &SARIFLocation{PhysicalLocation: physical}
+}
+
+// =============================================================================
+// GitHub Partial Fingerprints
+// =============================================================================
+
+// PrimaryLocationLineHashKey is the partialFingerprints key GitHub code
+// scanning uses to track alerts across commits.
+const PrimaryLocationLineHashKey = "primaryLocationLineHash"
+
+// GeneratePartialFingerprints returns SARIF partialFingerprints for a finding:
+//   - "rediver/v1": the RIS fingerprint, if set
+//   - "primaryLocationLineHash": a line hash of the finding's snippet, if the
+//     location has one
+//
+// The line hash is computed from the snippet only, not the source file, so it
+// approximates GitHub's value: GitHub hashes the 100 non-whitespace characters
+// following the start line in the file, and the two differ whenever the
+// snippet ends before that. The hash is still stable across commits as long
+// as the snippet is. Without a snippet, the key is omitted and GitHub's
+// upload action computes it from the checked-out source. Returns nil if
+// there is nothing to emit.
+func GeneratePartialFingerprints(f Finding) map[string]string {
+	fps := make(map[string]string, 2)
+	if f.Fingerprint != "" {
+		fps[sarifFingerprintKey] = f.Fingerprint
+	}
+	if f.Location != nil && f.Location.Snippet != "" {
+		if hashes := PrimaryLocationLineHashes(f.Location.Snippet); len(hashes) > 0 {
+			fps[PrimaryLocationLineHashKey] = hashes[0]
+		}
+	}
+	if len(fps) == 0 {
+		return nil
+	}
+	return fps
+}
+
+// PrimaryLocationLineHashes computes GitHub's primaryLocationLineHash for
+// every line of a source file; element i is the hash of line i+1, formatted
+// as "<hash>:<occurrence>". It ports the rolling hash of GitHub's
+// codeql-action: spaces, tabs and the LF of a CRLF are skipped, and each line
+// hashes the 100 characters (UTF-16 code units) that start it.
+func PrimaryLocationLineHashes(content string) []string {
+	if content == "" {
+		return nil
+	}
+
+	const (
+		blockSize = 100
+		mod       = uint64(37)
+		eof       = 0xFFFF
+	)
+	firstMod := uint64(1)
+	for i := 0; i < blockSize; i++ {
+		firstMod *= mod
+	}
+
+	var window [blockSize]uint64
+	var lineNumbers [blockSize]int
+	for i := range lineNumbers {
+		lineNumbers[i] = -1
+	}
+
+	var hash uint64
+	var hashes []string
+	counts := make(map[string]int)
+	index, lineNumber := 0, 0
+	lineStart, prevCR := true, false
+
+	outputHash := func() {
+		value := strconv.FormatUint(hash, 16)
+		counts[value]++
+		for len(hashes) < lineNumbers[index] {
+			hashes = append(hashes, "")
+		}
+		hashes[lineNumbers[index]-1] = value + ":" + strconv.Itoa(counts[value])
+		lineNumbers[index] = -1
+	}
+	updateHash := func(c uint64) {
+		begin := window[index]
+		window[index] = c
+		hash = mod*hash + c - firstMod*begin
+		index = (index + 1) % blockSize
+	}
+	process := func(c uint16) {
+		if c == ' ' || c == '\t' || (prevCR && c == '\n') {
+			prevCR = false
+			return
+		}
+		prevCR = c == '\r'
+		if prevCR {
+			c = '\n'
+		}
+		if lineNumbers[index] != -1 {
+			outputHash()
+		}
+		if lineStart {
+			lineStart = false
+			lineNumber++
+			lineNumbers[index] = lineNumber
+		}
+		if c == '\n' {
+			lineStart = true
+		}
+		updateHash(uint64(c))
+	}
+
+	for _, c := range utf16.Encode([]rune(content)) {
+		process(c)
+	}
+	process(eof)
+
+	// Flush the lines still in the window
+	for i := 0; i < blockSize; i++ {
+		if lineNumbers[index] != -1 {
+			outputHash()
+		}
+		updateHash(0)
+	}
+
+	return hashes
+}
+
+// severityToSARIFLevel converts RIS severity to a SARIF level.
+func severityToSARIFLevel(sev Severity) string {
+	switch sev {
+	case SeverityCritical, SeverityHigh:
+		return "error"
+	case SeverityMedium:
+		return "warning"
+	case SeverityLow:
+		return "note"
+	case SeverityInfo:
+		return "none"
+	default:
+		return "warning"
+	}
+}
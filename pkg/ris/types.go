@@ -856,10 +856,29 @@ type Finding struct {
 	// Suppression information (if finding is suppressed)
 	Suppression *Suppression `json:"suppression,omitempty"`
 
+	// Provenance records which scanner and scan run produced the finding
+	Provenance *Provenance `json:"provenance,omitempty"`
+
 	// Custom properties
 	Properties Properties `json:"properties,omitempty"`
 }
 
+// Provenance identifies the scanner and scan run that produced a finding,
+// for audit trails. It is nil for legacy data.
+type Provenance struct {
+	// Scanner name (e.g. semgrep, trivy)
+	Scanner string `json:"scanner,omitempty"`
+
+	// Scanner version
+	ScannerVersion string `json:"scanner_version,omitempty"`
+
+	// Scan run identifier (see ReportMetadata.ID)
+	ScanID string `json:"scan_id,omitempty"`
+
+	// When the scan ran
+	ScannedAt time.Time `json:"scanned_at"`
+}
+
 // FindingStatus represents the status of a finding.
 type FindingStatus string
 
@@ -920,6 +939,9 @@ type Suppression struct {
 
 	// When the finding was suppressed
 	SuppressedAt *time.Time `json:"suppressed_at,omitempty"`
+
+	// When the suppression expires (nil = never)
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
 
 // FindingLocation contains location information for code-based findings.
@@ -1047,6 +1069,10 @@ type VulnerabilityDetails struct {
 	// Is direct dependency (vs transitive)
 	IsDirect bool `json:"is_direct,omitempty"`
 
+	// Dependency depth: 1 for direct dependencies, 2+ for transitive ones,
+	// 0 if unknown
+	DependencyDepth int `json:"dependency_depth,omitempty"`
+
 	// Dependency path for transitive vulnerabilities
 	DependencyPath []string `json:"dependency_path,omitempty"`
 }
@@ -1065,6 +1091,10 @@ type SecretDetails struct {
 	// Length of the secret
 	Length int `json:"length,omitempty"`
 
+	// Short hash of the secret value (see fingerprint.SecretHash), for
+	// grouping occurrences of the same secret. Never the raw value.
+	ValueHash string `json:"value_hash,omitempty"`
+
 	// Entropy score
 	Entropy float64 `json:"entropy,omitempty"`
 
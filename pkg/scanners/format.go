@@ -0,0 +1,161 @@
+package scanners
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rediverio/sdk/pkg/ris"
+	"github.com/rediverio/sdk/pkg/scanners/gitleaks"
+	"github.com/rediverio/sdk/pkg/scanners/grype"
+	"github.com/rediverio/sdk/pkg/scanners/semgrep"
+	"github.com/rediverio/sdk/pkg/scanners/trivy"
+)
+
+// =============================================================================
+// Report Format Detection
+// =============================================================================
+
+// ReportFormat identifies the format of a scanner report.
+type ReportFormat string
+
+const (
+	ReportFormatUnknown   ReportFormat = ""
+	ReportFormatTrivy     ReportFormat = "trivy"
+	ReportFormatSemgrep   ReportFormat = "semgrep"
+	ReportFormatGitleaks  ReportFormat = "gitleaks"
+	ReportFormatGrype     ReportFormat = "grype"
+	ReportFormatSARIF     ReportFormat = "sarif"
+	ReportFormatCycloneDX ReportFormat = "cyclonedx"
+)
+
+var (
+	// ErrUnknownReportFormat is returned when a report matches no known format.
+	ErrUnknownReportFormat = errors.New("unknown report format")
+
+	// ErrUnsupportedReportFormat is returned by ParseReport for formats that
+	// are recognized but have no finding parser.
+	ErrUnsupportedReportFormat = errors.New("unsupported report format")
+)
+
+// DetectReportFormat identifies a JSON scanner report by structural markers,
+// without relying on file names or caller hints:
+//   - SARIF: a "runs" array with a SARIF "$schema" or version "2.1.x"
+//   - CycloneDX: "bomFormat": "CycloneDX"
+//   - Trivy: "SchemaVersion" with "ArtifactName" or "Results", or a
+//     top-level array of objects with "Target" (schema version 1)
+//   - Semgrep: a "results" array with "errors", "paths" or "version"
+//   - grype: a "matches" array with a "descriptor"
+//   - gitleaks: a top-level array of objects with "RuleID"; an empty array
+//     is also reported as gitleaks
+//
+// Returns ErrUnknownReportFormat for anything else, including invalid JSON.
+func DetectReportFormat(data []byte) (ReportFormat, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return ReportFormatUnknown, fmt.Errorf("%w: empty input", ErrUnknownReportFormat)
+	}
+
+	switch data[0] {
+	case '[':
+		var items []map[string]json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return ReportFormatUnknown, fmt.Errorf("%w: %v", ErrUnknownReportFormat, err)
+		}
+		if len(items) == 0 {
+			return ReportFormatGitleaks, nil
+		}
+		if _, ok := items[0]["RuleID"]; ok {
+			return ReportFormatGitleaks, nil
+		}
+		if _, ok := items[0]["Target"]; ok {
+			return ReportFormatTrivy, nil
+		}
+
+	case '{':
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return ReportFormatUnknown, fmt.Errorf("%w: %v", ErrUnknownReportFormat, err)
+		}
+		if format := detectObjectFormat(obj); format != ReportFormatUnknown {
+			return format, nil
+		}
+	}
+
+	return ReportFormatUnknown, ErrUnknownReportFormat
+}
+
+// detectObjectFormat identifies a report whose top level is a JSON object.
+func detectObjectFormat(obj map[string]json.RawMessage) ReportFormat {
+	has := func(key string) bool {
+		_, ok := obj[key]
+		return ok
+	}
+	str := func(key string) string {
+		var s string
+		_ = json.Unmarshal(obj[key], &s)
+		return s
+	}
+
+	switch {
+	case has("runs") && (strings.Contains(strings.ToLower(str("$schema")), "sarif") ||
+		strings.HasPrefix(str("version"), "2.1")):
+		return ReportFormatSARIF
+	case strings.EqualFold(str("bomFormat"), "CycloneDX"):
+		return ReportFormatCycloneDX
+	case has("SchemaVersion") && (has("ArtifactName") || has("Results")):
+		return ReportFormatTrivy
+	case has("results") && (has("errors") || has("paths") || has("version")):
+		return ReportFormatSemgrep
+	case has("matches") && has("descriptor"):
+		return ReportFormatGrype
+	default:
+		return ReportFormatUnknown
+	}
+}
+
+// ParseReport detects the format of a scanner report (see
+// DetectReportFormat) and converts it to RIS findings with the matching
+// parser. CycloneDX is recognized but returns ErrUnsupportedReportFormat,
+// since SBOMs carry components rather than findings.
+func ParseReport(data []byte) ([]ris.Finding, error) {
+	format, err := DetectReportFormat(data)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	var report *ris.Report
+	switch format {
+	case ReportFormatTrivy:
+		report, err = trivy.NewParser().Parse(ctx, data, nil)
+	case ReportFormatSemgrep:
+		report, err = (&semgrep.Parser{}).Parse(ctx, data, nil)
+	case ReportFormatGitleaks:
+		report, err = (&gitleaks.Parser{}).Parse(ctx, data, nil)
+	case ReportFormatGrype:
+		report, err = grype.NewParser().Parse(ctx, data, nil)
+	case ReportFormatSARIF:
+		report, err = ris.FromSARIF(data, nil)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedReportFormat, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s report: %w", format, err)
+	}
+
+	return report.Findings, nil
+}
+
+// ParseGrypeJSON converts grype JSON output ("grype -o json") to RIS SCA
+// findings. See the grype package for the parser and raw output types.
+func ParseGrypeJSON(data []byte) ([]ris.Finding, error) {
+	report, err := grype.ParseToRIS(data, nil)
+	if err != nil {
+		return nil, err
+	}
+	return report.Findings, nil
+}
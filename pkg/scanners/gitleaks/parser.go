@@ -7,6 +7,7 @@ import (
 
 	"github.com/rediverio/sdk/pkg/core"
 	"github.com/rediverio/sdk/pkg/ris"
+	"github.com/rediverio/sdk/pkg/shared/fingerprint"
 )
 
 // Parser converts gitleaks output to RIS format.
@@ -41,6 +42,9 @@ func (p *Parser) Parse(ctx context.Context, data []byte, opts *core.ParseOptions
 	report := ris.NewReport()
 	report.Metadata.SourceType = "scanner"
 	report.Metadata.Timestamp = time.Now()
+	if opts != nil && opts.ScanID != "" {
+		report.Metadata.ID = opts.ScanID
+	}
 
 	// Set tool info
 	report.Tool = &ris.Tool{
@@ -75,6 +79,8 @@ func (p *Parser) Parse(ctx context.Context, data []byte, opts *core.ParseOptions
 		report.Findings = append(report.Findings, risFinding)
 	}
 
+	report.ApplyProvenance()
+
 	return report, nil
 }
 
@@ -129,6 +135,7 @@ func (p *Parser) convertFinding(f Finding, index int, opts *core.ParseOptions) r
 		Service:     GetServiceName(f.RuleID),
 		MaskedValue: core.MaskSecret(f.Secret),
 		Length:      len(f.Secret),
+		ValueHash:   fingerprint.SecretHash(f.Secret),
 		Entropy:     f.Entropy,
 	}
 
@@ -173,6 +180,13 @@ func (p *Parser) convertFinding(f Finding, index int, opts *core.ParseOptions) r
 		GetServiceName(f.RuleID),
 	}
 
+	// Down-rank well-known public example and test credentials
+	if core.IsTestCredential(f.Secret) {
+		finding.Severity = ris.SeverityInfo
+		finding.Confidence = 10
+		finding.Tags = append(finding.Tags, "test-credential")
+	}
+
 	return finding
 }
 
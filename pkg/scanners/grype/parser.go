@@ -0,0 +1,358 @@
+package grype
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rediverio/sdk/pkg/core"
+	"github.com/rediverio/sdk/pkg/ris"
+)
+
+// Parser converts grype JSON output to RIS format.
+type Parser struct {
+	// Configuration
+	Verbose bool
+}
+
+// NewParser creates a new grype parser.
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// Name returns the parser name.
+func (p *Parser) Name() string {
+	return "grype"
+}
+
+// SupportedFormats returns supported output formats.
+func (p *Parser) SupportedFormats() []string {
+	return []string{"json", "grype"}
+}
+
+// CanParse checks if this parser can handle the data.
+func (p *Parser) CanParse(data []byte) bool {
+	var probe struct {
+		Matches    json.RawMessage `json:"matches"`
+		Descriptor *Descriptor     `json:"descriptor"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return false
+	}
+	return probe.Matches != nil && probe.Descriptor != nil && probe.Descriptor.Name == "grype"
+}
+
+// Parse converts grype JSON output to RIS report.
+func (p *Parser) Parse(ctx context.Context, data []byte, opts *core.ParseOptions) (*ris.Report, error) {
+	grypeReport, err := ParseJSONBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse grype output: %w", err)
+	}
+
+	// Create RIS report
+	report := ris.NewReport()
+	report.Metadata.SourceType = "scanner"
+	report.Metadata.Timestamp = time.Now()
+	if opts != nil && opts.ScanID != "" {
+		report.Metadata.ID = opts.ScanID
+	}
+
+	// Set tool info
+	report.Tool = &ris.Tool{
+		Name:         "grype",
+		Vendor:       "Anchore",
+		InfoURL:      "https://github.com/anchore/grype",
+		Capabilities: []string{"sca", "vulnerability_scanning", "container_scanning"},
+	}
+	if grypeReport.Descriptor != nil && grypeReport.Descriptor.Version != "" {
+		report.Tool.Version = grypeReport.Descriptor.Version
+	}
+
+	// Add asset if configured
+	if opts != nil && opts.AssetValue != "" {
+		assetID := opts.AssetID
+		if assetID == "" {
+			assetID = "asset-1"
+		}
+		report.Assets = append(report.Assets, ris.Asset{
+			ID:          assetID,
+			Type:        opts.AssetType,
+			Value:       opts.AssetValue,
+			Criticality: ris.CriticalityHigh,
+		})
+	}
+
+	// Convert matches
+	for i := range grypeReport.Matches {
+		report.Findings = append(report.Findings, p.convertMatch(&grypeReport.Matches[i], opts))
+	}
+
+	report.ApplyProvenance()
+
+	if p.Verbose {
+		fmt.Printf("[grype-parser] Parsed %d findings\n", len(report.Findings))
+	}
+
+	return report, nil
+}
+
+// convertMatch converts a grype match to a RIS SCA finding.
+func (p *Parser) convertMatch(m *Match, opts *core.ParseOptions) ris.Finding {
+	vuln := &m.Vulnerability
+	pkg := &m.Artifact
+
+	cveID := primaryCVE(m)
+	advisoryID := vuln.ID
+	if cveID != "" {
+		advisoryID = cveID
+	}
+
+	finding := ris.Finding{
+		ID:          vuln.ID,
+		Type:        ris.FindingTypeVulnerability,
+		Title:       fmt.Sprintf("%s: %s@%s", vuln.ID, pkg.Name, pkg.Version),
+		Description: description(m),
+		Severity:    ris.Severity(GetRISSeverity(vuln.Severity)),
+		Confidence:  100, // grype matches are deterministic
+		RuleID:      vuln.ID,
+		Category:    "vulnerability",
+		Fingerprint: core.GenerateScaFingerprint(pkg.Name, pkg.Version, advisoryID),
+	}
+	if opts != nil && opts.DefaultConfidence > 0 {
+		finding.Confidence = opts.DefaultConfidence
+	}
+
+	// Set location
+	if len(pkg.Locations) > 0 && pkg.Locations[0].Path != "" {
+		finding.Location = &ris.FindingLocation{
+			Path: pkg.Locations[0].Path,
+		}
+		if opts != nil {
+			finding.Location.Branch = opts.Branch
+			finding.Location.CommitSHA = opts.CommitSHA
+		}
+	}
+
+	ecosystem := pkg.Language
+	if ecosystem == "" {
+		ecosystem = pkg.Type
+	}
+	purl := pkg.PURL
+	if purl == "" && pkg.Name != "" {
+		purl = fmt.Sprintf("pkg:%s/%s@%s", purlType(pkg.Type), pkg.Name, pkg.Version)
+	}
+
+	// Set vulnerability details
+	finding.Vulnerability = &ris.VulnerabilityDetails{
+		CVEID:           cveID,
+		Package:         pkg.Name,
+		AffectedVersion: pkg.Version,
+		Ecosystem:       ecosystem,
+		PURL:            purl,
+	}
+	if best, version := bestCVSS(m); best != nil {
+		finding.Vulnerability.CVSSScore = best.Score
+		finding.Vulnerability.CVSSVector = best.Vector
+		if canonical, err := core.CanonicalizeVector(best.Vector); err == nil {
+			finding.Vulnerability.CVSSVector = canonical
+		}
+		finding.Vulnerability.CVSSSource = string(best.Source)
+		finding.Vulnerability.CVSSVersion = version
+	}
+	if vuln.Fix.State == "fixed" && len(vuln.Fix.Versions) > 0 {
+		finding.Vulnerability.FixedVersion = vuln.Fix.Versions[0]
+		finding.Vulnerability.FixedVersions = vuln.Fix.Versions
+		finding.Remediation = &ris.Remediation{
+			Recommendation: fmt.Sprintf("Upgrade %s from %s to %s", pkg.Name, pkg.Version, vuln.Fix.Versions[0]),
+			FixAvailable:   true,
+		}
+	}
+
+	// Set references
+	if vuln.DataSource != "" {
+		finding.References = append(finding.References, vuln.DataSource)
+	}
+	for _, u := range vuln.URLs {
+		if u != vuln.DataSource {
+			finding.References = append(finding.References, u)
+		}
+	}
+
+	// Set tags
+	finding.Tags = []string{"sca"}
+	if ecosystem != "" {
+		finding.Tags = append(finding.Tags, ecosystem)
+	}
+	if vuln.Fix.State != "" {
+		finding.Tags = append(finding.Tags, vuln.Fix.State)
+	}
+
+	return finding
+}
+
+// primaryCVE returns the CVE ID of a match: the vulnerability ID itself if it
+// is a CVE, else the first related CVE (grype reports GHSA and distro
+// advisories with the CVE as a related vulnerability).
+func primaryCVE(m *Match) string {
+	if strings.HasPrefix(strings.ToUpper(m.Vulnerability.ID), "CVE-") {
+		return m.Vulnerability.ID
+	}
+	for _, rel := range m.RelatedVulnerabilities {
+		if strings.HasPrefix(strings.ToUpper(rel.ID), "CVE-") {
+			return rel.ID
+		}
+	}
+	return ""
+}
+
+// description returns the vulnerability description, falling back to the
+// first related vulnerability that has one.
+func description(m *Match) string {
+	if m.Vulnerability.Description != "" {
+		return m.Vulnerability.Description
+	}
+	for _, rel := range m.RelatedVulnerabilities {
+		if rel.Description != "" {
+			return rel.Description
+		}
+	}
+	return ""
+}
+
+// bestCVSS collects the CVSS entries of a match and its related
+// vulnerabilities per source, then picks one by the SDK's CVSS source
+// priority (see core.SelectBestCVSSVector). Within a source the newest CVSS
+// version wins, then the highest score. Returns the chosen data and its
+// CVSS version.
+func bestCVSS(m *Match) (*core.CVSSData, string) {
+	type entry struct {
+		data    core.CVSSData
+		version string
+	}
+	bySource := make(map[core.CVSSSource]entry)
+
+	add := func(entries []CVSS, namespace string) {
+		for _, c := range entries {
+			if c.Metrics.BaseScore <= 0 {
+				continue
+			}
+			source := cvssSource(c.Source, namespace)
+			version := c.Version
+			if version == "" {
+				version = cvssVersionFromVector(c.Vector)
+			}
+			e := entry{
+				data: core.CVSSData{
+					Source:  source,
+					Score:   c.Metrics.BaseScore,
+					Vector:  c.Vector,
+					Version: core.ParseCVSSVersion(version),
+				},
+				version: version,
+			}
+			existing, ok := bySource[source]
+			switch {
+			case !ok,
+				e.version > existing.version,
+				e.version == existing.version && e.data.Score > existing.data.Score:
+				bySource[source] = e
+			}
+		}
+	}
+	add(m.Vulnerability.CVSS, m.Vulnerability.Namespace)
+	for _, rel := range m.RelatedVulnerabilities {
+		add(rel.CVSS, rel.Namespace)
+	}
+
+	cvssMap := make(map[core.CVSSSource]core.CVSSData, len(bySource))
+	for source, e := range bySource {
+		cvssMap[source] = e.data
+	}
+	best := core.SelectBestCVSSVector(cvssMap)
+	if best == nil {
+		// Sources outside the priority list (e.g. distro trackers)
+		for _, e := range bySource {
+			if best == nil || e.data.Score > best.Score {
+				data := e.data
+				best = &data
+			}
+		}
+	}
+	if best == nil {
+		return nil, ""
+	}
+	return best, bySource[best.Source].version
+}
+
+// cvssSource maps a grype CVSS source (an email-like identifier such as
+// "nvd@nist.gov") or, failing that, the record namespace ("nvd:cpe",
+// "github:language:python") to a CVSS source.
+func cvssSource(source, namespace string) core.CVSSSource {
+	for _, s := range []string{strings.ToLower(source), strings.ToLower(namespace)} {
+		switch {
+		case s == "":
+			continue
+		case strings.Contains(s, "nvd"):
+			return core.CVSSSourceNVD
+		case strings.Contains(s, "github"):
+			return core.CVSSSourceGHSA
+		case strings.Contains(s, "redhat"):
+			return core.CVSSSourceRedHat
+		case strings.Contains(s, "bitnami"):
+			return core.CVSSSourceBitnami
+		}
+	}
+	if source != "" {
+		return core.CVSSSource(strings.ToLower(source))
+	}
+	name, _, _ := strings.Cut(strings.ToLower(namespace), ":")
+	return core.CVSSSource(name)
+}
+
+// cvssVersionFromVector infers the CVSS version from a vector string.
+func cvssVersionFromVector(vector string) string {
+	if rest, ok := strings.CutPrefix(vector, "CVSS:"); ok {
+		version, _, _ := strings.Cut(rest, "/")
+		return version
+	}
+	if strings.Contains(vector, "AV:") {
+		return "2.0"
+	}
+	return ""
+}
+
+// purlType maps a grype artifact type to a package URL type.
+func purlType(artifactType string) string {
+	switch artifactType {
+	case "python":
+		return "pypi"
+	case "java-archive", "jenkins-plugin":
+		return "maven"
+	case "go-module", "go":
+		return "golang"
+	case "gem":
+		return "gem"
+	case "rust-crate":
+		return "cargo"
+	case "dotnet":
+		return "nuget"
+	case "php-composer":
+		return "composer"
+	case "apk":
+		return "apk"
+	case "deb":
+		return "deb"
+	case "rpm":
+		return "rpm"
+	default:
+		return strings.ToLower(artifactType)
+	}
+}
+
+// ParseToRIS is a convenience function to parse grype JSON to RIS.
+func ParseToRIS(data []byte, opts *core.ParseOptions) (*ris.Report, error) {
+	parser := NewParser()
+	return parser.Parse(context.Background(), data, opts)
+}
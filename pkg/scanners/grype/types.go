@@ -0,0 +1,140 @@
+package grype
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// =============================================================================
+// Grype JSON Output Types
+// =============================================================================
+
+// Report represents the root grype JSON output ("grype -o json").
+type Report struct {
+	Matches    []Match     `json:"matches"`
+	Source     *Source     `json:"source,omitempty"`
+	Distro     *Distro     `json:"distro,omitempty"`
+	Descriptor *Descriptor `json:"descriptor,omitempty"`
+}
+
+// Match pairs a vulnerability with the package (artifact) it was found in.
+type Match struct {
+	Vulnerability          Vulnerability          `json:"vulnerability"`
+	RelatedVulnerabilities []RelatedVulnerability `json:"relatedVulnerabilities,omitempty"`
+	Artifact               Artifact               `json:"artifact"`
+}
+
+// Vulnerability is the primary vulnerability record of a match.
+type Vulnerability struct {
+	ID          string   `json:"id"`
+	DataSource  string   `json:"dataSource,omitempty"`
+	Namespace   string   `json:"namespace,omitempty"` // e.g. nvd:cpe, github:language:python
+	Severity    string   `json:"severity,omitempty"`  // Critical, High, Medium, Low, Negligible, Unknown
+	URLs        []string `json:"urls,omitempty"`
+	Description string   `json:"description,omitempty"`
+	CVSS        []CVSS   `json:"cvss,omitempty"`
+	Fix         Fix      `json:"fix"`
+}
+
+// RelatedVulnerability is an alias record for the same issue, typically the
+// NVD CVE entry behind a GHSA or distro advisory.
+type RelatedVulnerability struct {
+	ID          string   `json:"id"`
+	DataSource  string   `json:"dataSource,omitempty"`
+	Namespace   string   `json:"namespace,omitempty"`
+	Severity    string   `json:"severity,omitempty"`
+	URLs        []string `json:"urls,omitempty"`
+	Description string   `json:"description,omitempty"`
+	CVSS        []CVSS   `json:"cvss,omitempty"`
+}
+
+// CVSS is one CVSS assessment of a vulnerability.
+type CVSS struct {
+	Source  string      `json:"source,omitempty"` // e.g. nvd@nist.gov
+	Type    string      `json:"type,omitempty"`   // Primary, Secondary
+	Version string      `json:"version,omitempty"`
+	Vector  string      `json:"vector,omitempty"`
+	Metrics CVSSMetrics `json:"metrics"`
+}
+
+// CVSSMetrics holds CVSS scores.
+type CVSSMetrics struct {
+	BaseScore           float64  `json:"baseScore"`
+	ExploitabilityScore *float64 `json:"exploitabilityScore,omitempty"`
+	ImpactScore         *float64 `json:"impactScore,omitempty"`
+}
+
+// Fix describes the fix state of a vulnerability.
+type Fix struct {
+	Versions []string `json:"versions,omitempty"`
+	State    string   `json:"state,omitempty"` // fixed, not-fixed, wont-fix, unknown
+}
+
+// Artifact is the package a vulnerability was matched against.
+type Artifact struct {
+	ID        string     `json:"id,omitempty"`
+	Name      string     `json:"name"`
+	Version   string     `json:"version"`
+	Type      string     `json:"type,omitempty"` // npm, python, java-archive, go-module, deb, apk, rpm, ...
+	Language  string     `json:"language,omitempty"`
+	Locations []Location `json:"locations,omitempty"`
+	Licenses  []string   `json:"licenses,omitempty"`
+	CPEs      []string   `json:"cpes,omitempty"`
+	PURL      string     `json:"purl,omitempty"`
+}
+
+// Location is where an artifact was found.
+type Location struct {
+	Path    string `json:"path"`
+	LayerID string `json:"layerID,omitempty"`
+}
+
+// Source describes the scanned target.
+type Source struct {
+	Type   string          `json:"type,omitempty"` // image, directory, file
+	Target json.RawMessage `json:"target,omitempty"`
+}
+
+// Distro describes the detected Linux distribution.
+type Distro struct {
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// Descriptor describes the grype run.
+type Descriptor struct {
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// =============================================================================
+// Helper Types
+// =============================================================================
+
+// SeverityMapping maps grype severity to RIS severity.
+var SeverityMapping = map[string]string{
+	"critical":   "critical",
+	"high":       "high",
+	"medium":     "medium",
+	"low":        "low",
+	"negligible": "info",
+	"unknown":    "info",
+}
+
+// GetRISSeverity converts grype severity to RIS severity.
+func GetRISSeverity(grypeSeverity string) string {
+	if s, ok := SeverityMapping[strings.ToLower(grypeSeverity)]; ok {
+		return s
+	}
+	return "info"
+}
+
+// ParseJSONBytes parses grype JSON output from bytes.
+func ParseJSONBytes(data []byte) (*Report, error) {
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse grype JSON: %w", err)
+	}
+	return &report, nil
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/rediverio/sdk/pkg/core"
 	"github.com/rediverio/sdk/pkg/ris"
 )
 
@@ -97,6 +98,8 @@ func (p *Parser) toRISReport(results []Result, target string) *ris.Report {
 		report.Findings = append(report.Findings, finding)
 	}
 
+	report.ApplyProvenance()
+
 	return report
 }
 
@@ -211,6 +214,9 @@ func (p *Parser) toRISFinding(result Result, assetRef string, index int) ris.Fin
 		if result.Info.Classification.CVSSScore > 0 {
 			finding.Vulnerability.CVSSScore = result.Info.Classification.CVSSScore
 			finding.Vulnerability.CVSSVector = result.Info.Classification.CVSSMetrics
+			if canonical, err := core.CanonicalizeVector(result.Info.Classification.CVSSMetrics); err == nil {
+				finding.Vulnerability.CVSSVector = canonical
+			}
 		}
 		if result.Info.Classification.EPSSScore > 0 {
 			finding.Vulnerability.EPSSScore = result.Info.Classification.EPSSScore
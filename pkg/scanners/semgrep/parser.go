@@ -41,6 +41,9 @@ func (p *Parser) Parse(ctx context.Context, data []byte, opts *core.ParseOptions
 	report := ris.NewReport()
 	report.Metadata.SourceType = "scanner"
 	report.Metadata.Timestamp = time.Now()
+	if opts != nil && opts.ScanID != "" {
+		report.Metadata.ID = opts.ScanID
+	}
 
 	// Set tool info
 	report.Tool = &ris.Tool{
@@ -78,6 +81,8 @@ func (p *Parser) Parse(ctx context.Context, data []byte, opts *core.ParseOptions
 		report.Findings = append(report.Findings, risFinding)
 	}
 
+	report.ApplyProvenance()
+
 	return report, nil
 }
 
@@ -35,20 +35,26 @@ func (p *Parser) SupportedFormats() []string {
 
 // CanParse checks if this parser can handle the data.
 func (p *Parser) CanParse(data []byte) bool {
-	// Try to parse as Trivy JSON
-	var report Report
-	if err := json.Unmarshal(data, &report); err != nil {
+	// Try to parse as Trivy JSON of a supported schema version
+	report, err := ParseReport(data)
+	if err != nil {
 		return false
 	}
 
+	// Legacy results must name their target, which tells them apart from
+	// other array formats such as gitleaks
+	if report.SchemaVersion == SchemaVersion1 {
+		return len(report.Results) > 0 && report.Results[0].Target != ""
+	}
+
 	// Check for Trivy-specific fields
 	return report.SchemaVersion > 0 || report.ArtifactType != "" || len(report.Results) > 0
 }
 
 // Parse converts Trivy JSON output to RIS report.
 func (p *Parser) Parse(ctx context.Context, data []byte, opts *core.ParseOptions) (*ris.Report, error) {
-	var trivyReport Report
-	if err := json.Unmarshal(data, &trivyReport); err != nil {
+	trivyReport, err := ParseReport(data)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse trivy output: %w", err)
 	}
 
@@ -61,17 +67,20 @@ func (p *Parser) Parse(ctx context.Context, data []byte, opts *core.ParseOptions
 		Timestamp:  time.Now(),
 		SourceType: "scanner",
 	}
+	if opts != nil && opts.ScanID != "" {
+		report.Metadata.ID = opts.ScanID
+	}
 
 	// Set tool info
 	report.Tool = &ris.Tool{
 		Name:         "trivy",
 		Vendor:       "Aqua Security",
-		Capabilities: p.inferCapabilities(&trivyReport),
+		Capabilities: p.inferCapabilities(trivyReport),
 	}
 
 	// Parse artifact as asset if available
 	if trivyReport.ArtifactName != "" {
-		asset := p.parseArtifactAsAsset(&trivyReport, opts)
+		asset := p.parseArtifactAsAsset(trivyReport, opts)
 		if asset != nil {
 			report.Assets = append(report.Assets, *asset)
 		}
@@ -80,8 +89,13 @@ func (p *Parser) Parse(ctx context.Context, data []byte, opts *core.ParseOptions
 	// Parse results
 	for _, result := range trivyReport.Results {
 		// Parse vulnerabilities
+		depths := dependencyDepths(result.Packages)
 		for _, vuln := range result.Vulnerabilities {
 			finding := p.parseVulnerability(&result, &vuln, opts)
+			if depth, ok := depths[vuln.PkgID]; ok && finding.Vulnerability != nil {
+				finding.Vulnerability.DependencyDepth = depth
+				finding.Vulnerability.IsDirect = depth == 1
+			}
 			report.Findings = append(report.Findings, finding)
 		}
 
@@ -104,6 +118,8 @@ func (p *Parser) Parse(ctx context.Context, data []byte, opts *core.ParseOptions
 		}
 	}
 
+	report.ApplyProvenance()
+
 	if p.Verbose {
 		fmt.Printf("[trivy-parser] Parsed %d findings from %s\n", len(report.Findings), trivyReport.ArtifactName)
 	}
@@ -111,6 +127,41 @@ func (p *Parser) Parse(ctx context.Context, data []byte, opts *core.ParseOptions
 	return report, nil
 }
 
+// dependencyDepths computes the depth of each package in the dependency
+// graph of a result, keyed by package ID: 1 for direct dependencies, 2 for
+// their dependencies, and so on. It needs the package relationships trivy
+// reports with --list-all-pkgs; packages not reachable from a direct
+// dependency are omitted.
+func dependencyDepths(pkgs []Package) map[string]int {
+	byID := make(map[string]*Package, len(pkgs))
+	depths := make(map[string]int)
+	var queue []string
+	for i := range pkgs {
+		byID[pkgs[i].ID] = &pkgs[i]
+		if pkgs[i].Relationship == "direct" {
+			depths[pkgs[i].ID] = 1
+			queue = append(queue, pkgs[i].ID)
+		}
+	}
+
+	// Breadth-first, so each package gets its shortest depth
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		pkg, ok := byID[id]
+		if !ok {
+			continue
+		}
+		for _, dep := range pkg.DependsOn {
+			if _, seen := depths[dep]; !seen {
+				depths[dep] = depths[id] + 1
+				queue = append(queue, dep)
+			}
+		}
+	}
+	return depths
+}
+
 // parseArtifactAsAsset converts Trivy artifact to RIS asset.
 func (p *Parser) parseArtifactAsAsset(report *Report, opts *core.ParseOptions) *ris.Asset {
 	if report.ArtifactName == "" {
@@ -151,6 +202,9 @@ func (p *Parser) parseArtifactAsAsset(report *Report, opts *core.ParseOptions) *
 func (p *Parser) parseVulnerability(result *Result, vuln *Vulnerability, opts *core.ParseOptions) ris.Finding {
 	// Get CVSS info
 	cvssScore, cvssVector, cvssSource := GetBestCVSSScore(vuln.CVSS)
+	if canonical, err := core.CanonicalizeVector(cvssVector); err == nil {
+		cvssVector = canonical
+	}
 
 	// Generate fingerprint
 	fingerprint := p.generateFingerprint(vuln.VulnerabilityID, vuln.PkgName, vuln.InstalledVersion, result.Target)
@@ -193,6 +247,12 @@ func (p *Parser) parseVulnerability(result *Result, vuln *Vulnerability, opts *c
 		Ecosystem:       result.Type,
 		PURL:            buildPURL(result.Type, vuln.PkgName, vuln.InstalledVersion),
 	}
+	if t, err := time.Parse(time.RFC3339, vuln.PublishedDate); err == nil {
+		finding.Vulnerability.PublishedAt = &t
+	}
+	if t, err := time.Parse(time.RFC3339, vuln.LastModifiedDate); err == nil {
+		finding.Vulnerability.ModifiedAt = &t
+	}
 
 	// Set references
 	if vuln.PrimaryURL != "" {
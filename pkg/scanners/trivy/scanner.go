@@ -302,13 +302,13 @@ func (s *Scanner) ScanSCA(ctx context.Context, target string, opts *core.ScaScan
 	}
 
 	// Parse output
-	var report Report
-	if err := json.Unmarshal(scanResult.RawOutput, &report); err != nil {
+	report, err := ParseReport(scanResult.RawOutput)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse trivy output: %w", err)
 	}
 
 	// Convert to ScaResult
-	result := s.convertToScaResult(&report, target)
+	result := s.convertToScaResult(report, target)
 	result.DurationMs = time.Since(start).Milliseconds()
 
 	if s.Verbose {
@@ -408,6 +408,9 @@ func (s *Scanner) convertToScaResult(report *Report, target string) *core.ScaRes
 		for _, vuln := range res.Vulnerabilities {
 			// Get CVSS info
 			cvssScore, cvssVector, cvssSource := GetBestCVSSScore(vuln.CVSS)
+			if canonical, err := core.CanonicalizeVector(cvssVector); err == nil {
+				cvssVector = canonical
+			}
 
 			// Generate fingerprint
 			fingerprint := generateFingerprint(vuln.VulnerabilityID, vuln.PkgName, vuln.InstalledVersion, target)
@@ -0,0 +1,76 @@
+package trivy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// =============================================================================
+// JSON Schema Versions
+// =============================================================================
+
+const (
+	// SchemaVersion1 is the layout of Trivy before v0.20: a top-level array
+	// of results, without artifact metadata.
+	SchemaVersion1 = 1
+
+	// SchemaVersion2 is the current layout: an object with SchemaVersion,
+	// ArtifactName, Metadata and Results.
+	SchemaVersion2 = 2
+)
+
+// DetectSchemaVersion returns the schema version of Trivy JSON output. A
+// top-level array is SchemaVersion1; an object reports its SchemaVersion
+// field, and an object without one is treated as SchemaVersion2, the only
+// object layout Trivy has produced. The version is not validated; see
+// ParseReport.
+func DetectSchemaVersion(data []byte) (int, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		return SchemaVersion1, nil
+	}
+
+	var header struct {
+		SchemaVersion int `json:"SchemaVersion"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return 0, err
+	}
+	if header.SchemaVersion == 0 {
+		return SchemaVersion2, nil
+	}
+	return header.SchemaVersion, nil
+}
+
+// ParseReport decodes Trivy JSON output of any supported schema version
+// into a Report. SchemaVersion1 results are wrapped in a Report with
+// SchemaVersion set to 1 and no artifact metadata; SchemaVersion2 reports
+// are decoded as-is. Unsupported versions are
+// an error rather than a best-effort parse, so a Trivy upgrade that changes
+// the layout fails loudly instead of yielding missing findings.
+func ParseReport(data []byte) (*Report, error) {
+	version, err := DetectSchemaVersion(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch version {
+	case SchemaVersion1:
+		var results []Result
+		if err := json.Unmarshal(data, &results); err != nil {
+			return nil, err
+		}
+		return &Report{SchemaVersion: SchemaVersion1, Results: results}, nil
+
+	case SchemaVersion2:
+		var report Report
+		if err := json.Unmarshal(data, &report); err != nil {
+			return nil, err
+		}
+		return &report, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported trivy schema version %d", version)
+	}
+}
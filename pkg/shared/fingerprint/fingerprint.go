@@ -9,8 +9,11 @@ package fingerprint
 import (
 	"crypto/sha256"
 	"encoding/hex"
-	"fmt"
+	"hash"
+	"io"
+	"strconv"
 	"strings"
+	"sync"
 )
 
 // Type represents the type of finding for fingerprint generation.
@@ -29,6 +32,9 @@ const (
 	// TypeMisconfiguration is for infrastructure/configuration findings.
 	TypeMisconfiguration Type = "misconfig"
 
+	// TypeContainer is for container image vulnerability findings.
+	TypeContainer Type = "container"
+
 	// TypeGeneric is for findings that don't fit other categories.
 	TypeGeneric Type = "generic"
 )
@@ -36,7 +42,7 @@ const (
 // Input contains the data needed to generate a fingerprint.
 // Not all fields are required - only the relevant ones for the finding type.
 type Input struct {
-	// Type of finding (sast, sca, secret, misconfig, generic)
+	// Type of finding (sast, sca, secret, misconfig, container, generic)
 	Type Type
 
 	// Common fields
@@ -44,6 +50,10 @@ type Input struct {
 	FilePath string // File path where finding was detected
 	Message  string // Finding message/description
 
+	// Rule ID normalization (opt-in, changes fingerprints of renamed rules)
+	Scanner         string // Scanner name, e.g. "semgrep"
+	NormalizeRuleID bool   // Map renamed rule IDs via NormalizeRuleID(Scanner, RuleID)
+
 	// Location fields (for SAST, Secret)
 	StartLine   int
 	EndLine     int
@@ -61,6 +71,9 @@ type Input struct {
 	// Misconfiguration-specific fields
 	ResourceType string // e.g., "aws_s3_bucket", "dockerfile"
 	ResourceName string // Resource identifier
+
+	// Container-specific fields (also uses PackageName, PackageVersion, VulnerabilityID)
+	ImageRef string // Image reference, normalized by the caller
 }
 
 // Generate creates a fingerprint for the given input.
@@ -72,65 +85,105 @@ type Input struct {
 //   - SCA: package + version + vuln ID (same vuln in same dependency)
 //   - Secret: file + rule + location + secret hash (same secret in same place)
 //   - Misconfig: resource + rule (same misconfiguration on same resource)
+//   - Container: image + package + version + vuln ID (layer-independent)
 //   - Generic: rule + file + location + message (fallback)
+//
+// If input.NormalizeRuleID is set, the rule ID is first mapped to its current
+// ID with NormalizeRuleID, so renamed rules keep their fingerprint.
 func Generate(input Input) string {
-	var data string
+	if input.NormalizeRuleID {
+		input.RuleID = NormalizeRuleID(input.Scanner, input.RuleID)
+	}
+
+	var h *fieldHasher
 
 	switch input.Type {
 	case TypeSAST:
 		// SAST: Deduplicate by file location and rule
 		// Same vulnerability in the same file/line should be the same finding
-		data = fmt.Sprintf("sast:%s:%s:%d:%d",
-			normalize(input.FilePath),
-			normalize(input.RuleID),
-			input.StartLine,
-			input.EndLine,
-		)
+		h = newFieldHasher("sast")
+		h.str(normalize(input.FilePath))
+		h.str(normalize(input.RuleID))
+		h.int(input.StartLine)
+		h.int(input.EndLine)
 
 	case TypeSCA:
 		// SCA: Deduplicate by package and vulnerability
 		// Same CVE in the same package version is the same finding
-		data = fmt.Sprintf("sca:%s:%s:%s",
-			normalize(input.PackageName),
-			normalize(input.PackageVersion),
-			normalize(input.VulnerabilityID),
-		)
+		h = newFieldHasher("sca")
+		h.str(normalize(input.PackageName))
+		h.str(normalize(input.PackageVersion))
+		h.str(normalize(input.VulnerabilityID))
 
 	case TypeSecret:
 		// Secret: Include secret hash to distinguish different secrets at same location
 		// This handles cases where multiple secrets exist on the same line
-		secretHash := ""
-		if input.SecretValue != "" {
-			secretHash = Hash(input.SecretValue)[:16] // First 16 chars
-		}
-		data = fmt.Sprintf("secret:%s:%s:%d:%s",
-			normalize(input.FilePath),
-			normalize(input.RuleID),
-			input.StartLine,
-			secretHash,
-		)
+		h = newFieldHasher("secret")
+		h.str(normalize(input.FilePath))
+		h.str(normalize(input.RuleID))
+		h.int(input.StartLine)
+		h.str(SecretHash(input.SecretValue))
 
 	case TypeMisconfiguration:
 		// Misconfig: Deduplicate by resource and rule
-		data = fmt.Sprintf("misconfig:%s:%s:%s:%s",
-			normalize(input.ResourceType),
-			normalize(input.ResourceName),
-			normalize(input.RuleID),
-			normalize(input.FilePath),
-		)
+		h = newFieldHasher("misconfig")
+		h.str(normalize(input.ResourceType))
+		h.str(normalize(input.ResourceName))
+		h.str(normalize(input.RuleID))
+		h.str(normalize(input.FilePath))
+
+	case TypeContainer:
+		// Container: Deduplicate by image and vulnerable package, not by layer
+		// The same package vuln surfacing in several layers is one finding
+		h = newFieldHasher("container")
+		h.str(normalize(input.ImageRef))
+		h.str(normalize(input.PackageName))
+		h.str(normalize(input.PackageVersion))
+		h.str(normalize(input.VulnerabilityID))
 
 	default:
 		// Generic: Use all available location data
-		data = fmt.Sprintf("generic:%s:%s:%d:%d:%s",
-			normalize(input.RuleID),
-			normalize(input.FilePath),
-			input.StartLine,
-			input.EndLine,
-			normalize(input.Message),
-		)
+		h = newFieldHasher("generic")
+		h.str(normalize(input.RuleID))
+		h.str(normalize(input.FilePath))
+		h.int(input.StartLine)
+		h.int(input.EndLine)
+		h.str(normalize(input.Message))
 	}
 
-	return Hash(data)
+	return h.sum()
+}
+
+// fieldHasher streams ":"-separated fingerprint fields into SHA256, hashing
+// the same bytes as Hash(prefix + ":" + field1 + ":" + ...) without building
+// the concatenated string, which matters for large messages and secrets.
+type fieldHasher struct {
+	h   hash.Hash
+	buf [20]byte // scratch space for formatting ints
+}
+
+// newFieldHasher starts a hash with the type prefix, e.g. "sast".
+func newFieldHasher(prefix string) *fieldHasher {
+	w := &fieldHasher{h: sha256.New()}
+	_, _ = io.WriteString(w.h, prefix)
+	return w
+}
+
+// str appends a string field.
+func (w *fieldHasher) str(s string) {
+	_, _ = io.WriteString(w.h, ":")
+	_, _ = io.WriteString(w.h, s)
+}
+
+// int appends an integer field in decimal, as %d formats it.
+func (w *fieldHasher) int(n int) {
+	_, _ = io.WriteString(w.h, ":")
+	_, _ = w.h.Write(strconv.AppendInt(w.buf[:0], int64(n), 10))
+}
+
+// sum returns the hash as 64 hex characters.
+func (w *fieldHasher) sum() string {
+	return hex.EncodeToString(w.h.Sum(nil))
 }
 
 // GenerateSAST creates a fingerprint for SAST/code vulnerability findings.
@@ -168,6 +221,94 @@ func GenerateSecret(filePath, ruleID string, startLine int, secretValue string)
 	})
 }
 
+// ScaInput identifies one SCA finding for batch fingerprinting.
+type ScaInput struct {
+	PackageName     string
+	PackageVersion  string
+	VulnerabilityID string
+}
+
+// FingerprintBatch computes SCA fingerprints for a batch of inputs.
+// The result has one fingerprint per input, in input order.
+func FingerprintBatch(inputs []ScaInput) []string {
+	result := make([]string, len(inputs))
+	for i, in := range inputs {
+		result[i] = GenerateSCA(in.PackageName, in.PackageVersion, in.VulnerabilityID)
+	}
+	return result
+}
+
+// parallelBatchThreshold is the batch size below which FingerprintBatchParallel
+// falls back to the serial implementation.
+//
+// Each fingerprint costs roughly 0.5µs, while spawning and joining a
+// goroutine costs a few µs, so sharding only pays off once each worker gets
+// a few hundred inputs. 1,000 is a conservative crossover; above it the
+// speedup approaches the number of cores. Re-measure on the target machine
+// with BenchmarkFingerprintBatch.
+const parallelBatchThreshold = 1000
+
+// FingerprintBatchParallel computes SCA fingerprints like FingerprintBatch but
+// shards the work across workers goroutines. Output order matches input order
+// and the result is identical regardless of the worker count. workers <= 1,
+// or batches below the crossover size, use the serial path.
+func FingerprintBatchParallel(inputs []ScaInput, workers int) []string {
+	if workers <= 1 || len(inputs) < parallelBatchThreshold {
+		return FingerprintBatch(inputs)
+	}
+	if workers > len(inputs) {
+		workers = len(inputs)
+	}
+
+	result := make([]string, len(inputs))
+	chunk := (len(inputs) + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for start := 0; start < len(inputs); start += chunk {
+		end := min(start+chunk, len(inputs))
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			// Each goroutine writes a disjoint index range; no locking needed
+			for i := start; i < end; i++ {
+				in := inputs[i]
+				result[i] = GenerateSCA(in.PackageName, in.PackageVersion, in.VulnerabilityID)
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	return result
+}
+
+// secretHashLength is the number of hex characters of SecretValueHash that
+// SecretHash keeps, and thus the length embedded in secret fingerprints.
+const secretHashLength = 16
+
+// SecretValueHash returns the canonical hash of a secret value: the full
+// 64-character hex SHA256 hash, or "" for an empty value. Storage and
+// secret-grouping code should use it to correlate the same secret across
+// files and scans. SecretHash and secret fingerprints use its first 16
+// characters, so a stored SecretValueHash h matches SecretHash via h[:16].
+func SecretValueHash(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return Hash(secret)
+}
+
+// SecretHash returns the short hash of a secret value used in secret
+// fingerprints: the first 16 hex characters of SecretValueHash, or "" for an
+// empty value. It is safe to store in place of the raw secret and can be used
+// to correlate the same secret across files.
+func SecretHash(secret string) string {
+	h := SecretValueHash(secret)
+	if h == "" {
+		return ""
+	}
+	return h[:secretHashLength]
+}
+
 // GenerateMisconfiguration creates a fingerprint for misconfiguration findings.
 func GenerateMisconfiguration(resourceType, resourceName, ruleID, filePath string) string {
 	return Generate(Input{
@@ -179,6 +320,19 @@ func GenerateMisconfiguration(resourceType, resourceName, ruleID, filePath strin
 	})
 }
 
+// GenerateContainer creates a fingerprint for container image vulnerability findings.
+// The image reference should already be normalized so that equivalent
+// references produce the same fingerprint.
+func GenerateContainer(imageRef, packageName, packageVersion, vulnID string) string {
+	return Generate(Input{
+		Type:            TypeContainer,
+		ImageRef:        imageRef,
+		PackageName:     packageName,
+		PackageVersion:  packageVersion,
+		VulnerabilityID: vulnID,
+	})
+}
+
 // GenerateGeneric creates a fingerprint for generic findings.
 // Use this when the finding type doesn't fit other categories.
 func GenerateGeneric(ruleID, filePath string, startLine, endLine int, message string) string {
@@ -245,3 +399,20 @@ func GenerateAuto(input Input) string {
 	}
 	return Generate(input)
 }
+
+// IsValidFingerprint reports whether fp is a well-formed fingerprint:
+// exactly 64 lowercase hex characters, as produced by Generate and Hash.
+// Storage layers should use this to reject truncated or corrupted values
+// before indexing.
+func IsValidFingerprint(fp string) bool {
+	if len(fp) != 64 {
+		return false
+	}
+	for i := 0; i < len(fp); i++ {
+		c := fp[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+package fingerprint
+
+import (
+	"strings"
+	"sync"
+)
+
+// maxRuleIDAliasHops bounds alias chain resolution (a -> b -> c) so that a
+// misconfigured cycle cannot loop forever.
+const maxRuleIDAliasHops = 8
+
+// RuleIDNormalizer maps rule IDs that scanners renamed between versions to
+// their current ID, so findings keep the same fingerprint across scanner
+// upgrades. It is safe for concurrent use.
+type RuleIDNormalizer struct {
+	mu      sync.RWMutex
+	aliases map[string]map[string]string // scanner -> old rule ID -> new rule ID
+}
+
+// NewRuleIDNormalizer creates an empty normalizer.
+func NewRuleIDNormalizer() *RuleIDNormalizer {
+	return &RuleIDNormalizer{aliases: make(map[string]map[string]string)}
+}
+
+// Register records that scanner renamed oldID to newID. Scanner names are
+// case-insensitive; rule IDs are compared as normalized for fingerprints
+// (trimmed, lower-case).
+func (n *RuleIDNormalizer) Register(scanner, oldID, newID string) {
+	scanner = normalize(scanner)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.aliases[scanner] == nil {
+		n.aliases[scanner] = make(map[string]string)
+	}
+	n.aliases[scanner][normalize(oldID)] = newID
+}
+
+// Normalize returns the current ID of a scanner rule, following chains of
+// renames. Unknown rule IDs are returned unchanged.
+func (n *RuleIDNormalizer) Normalize(scanner, ruleID string) string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	aliases := n.aliases[normalize(scanner)]
+	if aliases == nil {
+		return ruleID
+	}
+	for range maxRuleIDAliasHops {
+		newID, ok := aliases[normalize(ruleID)]
+		if !ok || strings.EqualFold(newID, ruleID) {
+			break
+		}
+		ruleID = newID
+	}
+	return ruleID
+}
+
+// DefaultRuleIDNormalizer is the normalizer used by NormalizeRuleID and by
+// Generate when Input.NormalizeRuleID is set. It is seeded with known
+// renames; register more with RegisterRuleIDAlias.
+var DefaultRuleIDNormalizer = NewRuleIDNormalizer()
+
+func init() {
+	// Semgrep registry rules gained their rule name as the last ID segment.
+	DefaultRuleIDNormalizer.Register("semgrep",
+		"python.lang.security.audit.dangerous-subprocess-use",
+		"python.lang.security.audit.dangerous-subprocess-use.dangerous-subprocess-use")
+	DefaultRuleIDNormalizer.Register("semgrep",
+		"javascript.browser.security.insecure-document-method",
+		"javascript.browser.security.insecure-document-method.insecure-document-method")
+}
+
+// RegisterRuleIDAlias records a rule rename in DefaultRuleIDNormalizer.
+func RegisterRuleIDAlias(scanner, oldID, newID string) {
+	DefaultRuleIDNormalizer.Register(scanner, oldID, newID)
+}
+
+// NormalizeRuleID returns the current ID of a scanner rule using
+// DefaultRuleIDNormalizer.
+func NormalizeRuleID(scanner, ruleID string) string {
+	return DefaultRuleIDNormalizer.Normalize(scanner, ruleID)
+}
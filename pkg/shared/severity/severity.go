@@ -5,7 +5,10 @@
 // Any changes must be backward compatible or coordinated across both projects.
 package severity
 
-import "strings"
+import (
+	"strings"
+	"sync"
+)
 
 // Level represents a severity level for security findings.
 type Level string
@@ -69,14 +72,60 @@ func (l Level) IsAtLeast(other Level) bool {
 	return l.Priority() >= other.Priority()
 }
 
+// =============================================================================
+// Severity Aliases - Scanner-specific labels
+// =============================================================================
+
+var (
+	aliasMu sync.RWMutex
+
+	// aliases maps upper-cased scanner-specific labels to a standard Level.
+	// Seeded with SonarQube and grype labels.
+	aliases = map[string]Level{
+		"BLOCKER":    Critical, // SonarQube
+		"MAJOR":      High,     // SonarQube
+		"MINOR":      Low,      // SonarQube
+		"NEGLIGIBLE": Info,     // grype
+	}
+)
+
+// RegisterAlias maps a scanner-specific severity label to a standard Level.
+// Aliases are case-insensitive and take precedence over the built-in mappings
+// in FromString. Registering an existing alias overwrites it.
+func RegisterAlias(alias string, level Level) {
+	key := strings.ToUpper(strings.TrimSpace(alias))
+	if key == "" {
+		return
+	}
+	aliasMu.Lock()
+	defer aliasMu.Unlock()
+	aliases[key] = level
+}
+
+// lookupAlias returns the Level registered for the given upper-cased label.
+func lookupAlias(key string) (Level, bool) {
+	aliasMu.RLock()
+	defer aliasMu.RUnlock()
+	level, ok := aliases[key]
+	return level, ok
+}
+
 // FromString normalizes various severity string formats to a standard Level.
-// Handles common formats from different scanners:
+// Registered aliases (see RegisterAlias) are consulted first, then the
+// built-in mappings. Handles common formats from different scanners:
 //   - Semgrep: ERROR, WARNING, INFO
 //   - Trivy: CRITICAL, HIGH, MEDIUM, LOW, UNKNOWN
 //   - Gitleaks: (uses rule-based)
 //   - SARIF: error, warning, note
+//   - SonarQube: BLOCKER, MAJOR, MINOR (via aliases)
+//   - Grype: Negligible (via aliases)
 func FromString(s string) Level {
-	switch strings.ToUpper(strings.TrimSpace(s)) {
+	key := strings.ToUpper(strings.TrimSpace(s))
+	if level, ok := lookupAlias(key); ok {
+		return level
+	}
+
+	switch key {
 	case "CRITICAL", "CRIT":
 		return Critical
 	case "HIGH", "ERROR", "SEVERE":
@@ -114,6 +163,22 @@ func FromCVSS(score float64) Level {
 	}
 }
 
+// FromCVSSv2 converts a CVSS v2 score (0.0-10.0) to a severity level.
+// CVSS v2 has no Critical or None rating; NVD uses:
+//   - 7.0-10.0: High
+//   - 4.0-6.9: Medium
+//   - 0.0-3.9: Low
+func FromCVSSv2(score float64) Level {
+	switch {
+	case score >= 7.0:
+		return High
+	case score >= 4.0:
+		return Medium
+	default:
+		return Low
+	}
+}
+
 // ToCVSSRange returns the CVSS score range for a severity level.
 // Returns (min, max) where min is inclusive and max is exclusive.
 func (l Level) ToCVSSRange() (float64, float64) {